@@ -3,15 +3,21 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/acai-travel/tech-challenge/internal/pb"
+	"github.com/twitchtv/twirp"
 )
 
+// conflictRetries is the number of times a message is resent when the conversation was updated concurrently.
+const conflictRetries = 3
+
 func main() {
 	flag.Usage = func() {
 		fmt.Printf("Usage: acai-cli [command] [options]\n")
@@ -19,6 +25,10 @@ func main() {
 		fmt.Println("  ask        Create a new conversation with assistant or continue an existing one")
 		fmt.Println("  list       List existing conversations")
 		fmt.Println("  show       Show conversation by ID")
+		fmt.Println("  delete     Delete conversation by ID")
+		fmt.Println("  search     Search conversations by title and message content")
+		fmt.Println("  pin        Pin a message, so it is always sent to the model")
+		fmt.Println("  unpin      Unpin a message")
 	}
 
 	if len(os.Args) < 2 {
@@ -36,6 +46,16 @@ func main() {
 	cli := pb.NewChatServiceJSONClient(url, http.DefaultClient)
 	ctx := context.Background()
 
+	// API_TOKEN holds an API key or a JWT, sent as a bearer token.
+	if v := os.Getenv("API_TOKEN"); v != "" {
+		var err error
+		ctx, err = twirp.WithHTTPRequestHeaders(ctx, http.Header{"Authorization": {"Bearer " + v}})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	switch os.Args[1] {
 	case "ask":
 		fmt.Println("Press CMD+C to exit.")
@@ -56,7 +76,7 @@ func main() {
 			fmt.Println("Timestamp:", resp.GetConversation().GetTimestamp().AsTime().Format(time.RFC1123))
 			fmt.Println("")
 			for _, msg := range resp.GetConversation().GetMessages() {
-				fmt.Printf("%s, %s:\n%s\n\n", msg.GetRole(), msg.GetTimestamp().AsTime().Format(time.TimeOnly), msg.GetContent())
+				printMessage(msg)
 			}
 		} else {
 			fmt.Println("Starting a new conversation, type your message below.")
@@ -95,7 +115,7 @@ func main() {
 				continue
 			}
 
-			out, err := cli.ContinueConversation(ctx, &pb.ContinueConversationRequest{
+			out, err := continueConversation(ctx, cli, &pb.ContinueConversationRequest{
 				ConversationId: cid,
 				Message:        string(line),
 			})
@@ -109,7 +129,18 @@ func main() {
 		}
 
 	case "list":
-		resp, err := cli.ListConversations(ctx, &pb.ListConversationsRequest{})
+		flags := flag.NewFlagSet("list", flag.ExitOnError)
+		pageSize := flags.Int("n", 0, "maximum number of conversations to list")
+		pageToken := flags.String("page", "", "page token printed by a previous list")
+		title := flags.String("title", "", "only list conversations whose title contains this text")
+		_ = flags.Parse(os.Args[2:])
+
+		resp, err := cli.ListConversations(ctx, &pb.ListConversationsRequest{
+			PageSize:      int32(*pageSize),
+			PageToken:     *pageToken,
+			TitleContains: *title,
+		})
+
 		if err != nil {
 			fmt.Printf("Error listing conversations: %v\n", err)
 			os.Exit(1)
@@ -124,6 +155,11 @@ func main() {
 		for _, conv := range resp.Conversations {
 			fmt.Printf("%s   %s\n", conv.GetId(), conv.GetTitle())
 		}
+
+		if resp.GetNextPageToken() != "" {
+			fmt.Println()
+			fmt.Println("More conversations available, use -page", resp.GetNextPageToken())
+		}
 	case "show":
 		if len(os.Args) < 3 {
 			fmt.Println("Error: Conversation ID is required")
@@ -142,9 +178,114 @@ func main() {
 		fmt.Println("ID:", resp.GetConversation().GetId())
 		fmt.Println("Title:", resp.GetConversation().GetTitle())
 		fmt.Println("Timestamp:", resp.GetConversation().GetTimestamp().AsTime().Format(time.RFC1123))
+		fmt.Println("Usage:", formatUsage(resp.GetConversation().GetUsage()))
 		fmt.Println("")
 		for _, msg := range resp.GetConversation().GetMessages() {
-			fmt.Printf("%s, %s:\n%s\n\n", msg.GetRole(), msg.GetTimestamp().AsTime().Format(time.TimeOnly), msg.GetContent())
+			printMessage(msg)
 		}
+	case "delete":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: Conversation ID is required")
+			os.Exit(1)
+		}
+
+		_, err := cli.DeleteConversation(ctx, &pb.DeleteConversationRequest{
+			ConversationId: os.Args[2],
+		})
+
+		if err != nil {
+			fmt.Printf("Error deleting conversation: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Conversation deleted:", os.Args[2])
+	case "search":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: Search query is required")
+			os.Exit(1)
+		}
+
+		resp, err := cli.SearchConversations(ctx, &pb.SearchConversationsRequest{
+			Query: strings.Join(os.Args[2:], " "),
+		})
+
+		if err != nil {
+			fmt.Printf("Error searching conversations: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(resp.GetResults()) == 0 {
+			fmt.Println("No conversations found.")
+			return
+		}
+
+		for _, res := range resp.GetResults() {
+			fmt.Printf("%s   %s\n", res.GetConversation().GetId(), res.GetConversation().GetTitle())
+			for i, snippet := range res.GetSnippets() {
+				fmt.Printf("  %s: %s\n", res.GetMessageIds()[i], snippet)
+			}
+			fmt.Println()
+		}
+	case "pin", "unpin":
+		if len(os.Args) < 4 {
+			fmt.Println("Error: Conversation ID and message ID are required")
+			os.Exit(1)
+		}
+
+		_, err := cli.PinMessage(ctx, &pb.PinMessageRequest{
+			ConversationId: os.Args[2],
+			MessageId:      os.Args[3],
+			Pinned:         os.Args[1] == "pin",
+		})
+
+		if err != nil {
+			fmt.Printf("Error pinning message: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Message %sned: %s\n", os.Args[1], os.Args[3])
+	}
+}
+
+// printMessage prints a conversation message, including the tools an assistant message called.
+func printMessage(msg *pb.Conversation_Message) {
+	content := msg.GetContent()
+	for _, call := range msg.GetToolCalls() {
+		content = strings.TrimSpace(content + "\n-> " + call.GetName() + " " + call.GetArguments())
+	}
+
+	if msg.GetRole() == pb.Conversation_TOOL {
+		content = "<- " + msg.GetToolName() + " " + content
+	}
+
+	header := fmt.Sprintf("%s, %s, %s", msg.GetRole(), msg.GetTimestamp().AsTime().Format(time.TimeOnly), msg.GetId())
+	if msg.GetPinned() {
+		header += " (pinned)"
+	}
+
+	if msg.GetUsage() != nil {
+		header += ", " + msg.GetModel() + " " + formatUsage(msg.GetUsage())
+	}
+
+	fmt.Printf("%s:\n%s\n\n", header, content)
+}
+
+func formatUsage(u *pb.Usage) string {
+	return fmt.Sprintf("%d tokens (%d prompt, %d completion), $%.4f", u.GetTotalTokens(), u.GetPromptTokens(), u.GetCompletionTokens(), u.GetEstimatedCost())
+}
+
+// continueConversation sends the message, resending it when another message to the same conversation was stored
+// while the reply was generated.
+func continueConversation(ctx context.Context, cli pb.ChatService, req *pb.ContinueConversationRequest) (*pb.ContinueConversationResponse, error) {
+	for attempt := 1; ; attempt++ {
+		out, err := cli.ContinueConversation(ctx, req)
+
+		var terr twirp.Error
+		if !errors.As(err, &terr) || terr.Code() != twirp.Aborted || attempt > conflictRetries {
+			return out, err
+		}
+
+		fmt.Println("The conversation was updated concurrently, retrying...")
+		time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
 	}
 }
@@ -0,0 +1,31 @@
+// Command migrate moves the messages embedded in the conversation documents of earlier versions to their own
+// collection. It uses the same MONGODB_URI and MONGODB_DATABASE variables as the server, and is safe to run more than
+// once.
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/mongox"
+)
+
+func main() {
+	migrated, err := migrate(context.Background(), model.New(mongox.MustConnect()))
+	if err != nil {
+		log.Fatalf("migration stopped after %d conversations: %v", migrated, err)
+	}
+
+	slog.Info("Migrated conversation messages", "conversations", migrated)
+}
+
+// migrate rebuilds the indexes and moves the embedded messages, returning the number of conversations migrated.
+func migrate(ctx context.Context, repo *model.Repository) (int, error) {
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return 0, err
+	}
+
+	return repo.MigrateMessages(ctx)
+}
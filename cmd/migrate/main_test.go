@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	. "github.com/acai-travel/tech-challenge/internal/chat/testing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMigrate(t *testing.T) {
+	SkipWithoutMongo(t)
+
+	ctx := context.Background()
+	db := ConnectMongo()
+	repo := model.New(db)
+
+	created := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	legacy := &model.Conversation{
+		ID:        primitive.NewObjectID(),
+		Title:     "Legacy conversation",
+		CreatedAt: created,
+		UpdatedAt: created,
+		Messages: []*model.Message{
+			{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: "Is it raining in Barcelona?", CreatedAt: created, UpdatedAt: created},
+			{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "No, it is sunny.", CreatedAt: created.Add(time.Second), UpdatedAt: created.Add(time.Second)},
+		},
+	}
+
+	// Conversations of earlier versions embed their messages.
+	if _, err := db.Collection("conversations").InsertOne(ctx, legacy); err != nil {
+		t.Fatalf("failed to seed conversation: %v", err)
+	}
+
+	defer func() {
+		_, _ = db.Collection("conversations").DeleteOne(ctx, bson.D{{Key: "_id", Value: legacy.ID}})
+		_, _ = db.Collection("messages").DeleteMany(ctx, bson.D{{Key: "conversation_id", Value: legacy.ID}})
+	}()
+
+	if pending, err := repo.PendingMigration(ctx); err != nil || !pending {
+		t.Errorf("expected a pending migration, got %t, err %v", pending, err)
+	}
+
+	migrated, err := migrate(ctx, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if migrated < 1 {
+		t.Errorf("expected the conversation to be migrated, got %d conversations", migrated)
+	}
+
+	// A second run finds nothing left to migrate.
+	if migrated, err := migrate(ctx, repo); err != nil || migrated != 0 {
+		t.Errorf("expected nothing to migrate again, got %d conversations, err %v", migrated, err)
+	}
+
+	if pending, err := repo.PendingMigration(ctx); err != nil || pending {
+		t.Errorf("expected no pending migration, got %t, err %v", pending, err)
+	}
+
+	embedded, err := db.Collection("conversations").CountDocuments(ctx, bson.D{
+		{Key: "_id", Value: legacy.ID},
+		{Key: "messages", Value: bson.D{{Key: "$exists", Value: true}}},
+	})
+
+	if err != nil || embedded != 0 {
+		t.Errorf("expected the embedded messages to be removed, got %d, err %v", embedded, err)
+	}
+
+	stored, err := db.Collection("messages").CountDocuments(ctx, bson.D{{Key: "conversation_id", Value: legacy.ID}})
+	if err != nil || stored != int64(len(legacy.Messages)) {
+		t.Errorf("expected %d stored messages, got %d, err %v", len(legacy.Messages), stored, err)
+	}
+
+	c, err := repo.DescribeConversation(ctx, legacy.ID.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(c.Messages) != len(legacy.Messages) {
+		t.Fatalf("expected %d messages, got %d", len(legacy.Messages), len(c.Messages))
+	}
+
+	for i, m := range c.Messages {
+		if m.ID != legacy.Messages[i].ID || m.Content != legacy.Messages[i].Content {
+			t.Errorf("message %d: expected %+v, got %+v", i, legacy.Messages[i], m)
+		}
+	}
+}
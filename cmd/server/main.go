@@ -1,20 +1,29 @@
 package main
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/acai-travel/tech-challenge/internal/observability"
 	"log"
 	"log/slog"
 	"net/http"
+	"os"
 
 	"context"
 	"github.com/acai-travel/tech-challenge/internal/chat"
 	"github.com/acai-travel/tech-challenge/internal/chat/assistant"
+	"github.com/acai-travel/tech-challenge/internal/chat/assistant/tools"
 	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/holidays"
 	"github.com/acai-travel/tech-challenge/internal/httpx"
+	"github.com/acai-travel/tech-challenge/internal/llm"
 	"github.com/acai-travel/tech-challenge/internal/mongox"
 	"github.com/acai-travel/tech-challenge/internal/pb"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
+	"github.com/acai-travel/tech-challenge/internal/weather"
 	"github.com/gorilla/mux"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/twitchtv/twirp"
 )
 
@@ -25,12 +34,85 @@ func main() {
 	}
 	defer shutdown(context.Background())
 
-	mongo := mongox.MustConnect()
+	shutdownMeter, err := observability.InitMeter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer shutdownMeter(context.Background())
 
-	repo := model.New(mongo)
-	assist := assistant.New()
+	var repo model.ConversationStore
+	switch store := os.Getenv("CONVERSATION_STORE"); store {
+	case "", "mongo":
+		mongo := model.New(mongox.MustConnect())
+		if err := mongo.EnsureIndexes(context.Background()); err != nil {
+			log.Fatal(err)
+		}
 
-	server := chat.NewServer(repo, assist)
+		// Conversations of earlier versions would be served without their history.
+		if pending, err := mongo.PendingMigration(context.Background()); err != nil {
+			log.Fatal(err)
+		} else if pending {
+			log.Fatal("conversations embed their messages, run `go run ./cmd/migrate` before starting the server")
+		}
+		repo = mongo
+	case "memory":
+		slog.Warn("Conversations are kept in memory and will be lost on restart")
+		repo = model.NewMemoryStore()
+	case "sqlite", "postgres":
+		repo = mustOpenSQL(store)
+	default:
+		log.Fatalf("unknown conversation store %q", store)
+	}
+
+	provider, err := llm.NewFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	weatherClient := weather.NewClient(weather.ConfigFromEnv())
+
+	registry, err := holidays.RegistryFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The seed file, if any, is the default calendar of the registry.
+	calendarConfig := holidays.ConfigFromEnv()
+	calendarConfig.Link = registry.Calendars[registry.Default].Link()
+
+	calendars, err := holidays.New(calendarConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go calendars.Run(context.Background())
+
+	assist := assistant.New(provider,
+		tools.NewWeatherTool(weatherClient),
+		tools.NewTodayTool(weatherClient, nil),
+		tools.NewWeatherForecastTool(weatherClient),
+		tools.NewWeatherAlertsTool(weatherClient),
+		tools.NewAirQualityTool(weatherClient),
+		tools.NewHolidaysTool(calendars, registry),
+	)
+
+	tenants, err := tenant.LoadFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if tenants != nil {
+		if err := tenants.Validate(assist.ToolNames()); err != nil {
+			log.Fatal(err)
+		}
+		slog.Info("Loaded tenants", "count", len(tenants.Tenants), "default", tenants.Default)
+	}
+
+	quota := chat.QuotaFromEnv()
+	if quota.Daily > 0 {
+		slog.Info("Enforcing daily token quota", "tokens", quota.Daily)
+	}
+
+	server := chat.NewServer(repo, assist, chat.WithQuota(quota))
 
 	// Configure handler
 	handler := mux.NewRouter()
@@ -43,7 +125,24 @@ func main() {
 		_, _ = fmt.Fprint(w, "Hi, my name is Clippy!")
 	})
 
-	handler.PathPrefix("/twirp/").Handler(pb.NewChatServiceServer(server, twirp.WithServerJSONSkipDefaults(true)))
+	authConfig := httpx.AuthConfigFromEnv()
+	if !authConfig.Enabled() {
+		slog.Warn("Authentication is disabled, set AUTH_API_KEYS or AUTH_JWT_SECRET to give users their own conversations")
+	}
+
+	auth := httpx.Auth(authConfig)
+	rateLimit := httpx.RateLimiter(httpx.NewMemoryBuckets(), httpx.RateLimitFromEnv())
+
+	// Tenants are resolved and requests rate limited once the caller is authenticated, as they depend on it.
+	api := func(h http.Handler) http.Handler {
+		if tenants != nil {
+			h = tenants.Middleware()(h)
+		}
+		return auth(rateLimit(h))
+	}
+
+	handler.PathPrefix("/twirp/").Handler(api(pb.NewChatServiceServer(server, twirp.WithServerJSONSkipDefaults(true))))
+	handler.PathPrefix(chat.StreamPathPrefix).Handler(api(server.StreamHandler()))
 
 	// Start the server
 	slog.Info("Starting the server...")
@@ -51,3 +150,33 @@ func main() {
 		panic(err)
 	}
 }
+
+// mustOpenSQL opens the SQL database at DATABASE_URL with the given driver and applies the pending migrations.
+// SQLite defaults to a local acai.db file.
+func mustOpenSQL(driver string) *model.SQLStore {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" && driver == "sqlite" {
+		dsn = "file:acai.db?_foreign_keys=on&_busy_timeout=5000"
+	}
+
+	if driver == "sqlite" {
+		driver = "sqlite3"
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if driver == "sqlite3" {
+		// SQLite allows a single writer, serializing access avoids "database is locked" errors.
+		db.SetMaxOpenConns(1)
+	}
+
+	store := model.NewSQLStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
+	return store
+}
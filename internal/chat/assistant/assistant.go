@@ -3,89 +3,119 @@ package assistant
 import (
 	"context"
 	"errors"
-	"github.com/acai-travel/tech-challenge/internal/chat/assistant/tools"
 	"github.com/acai-travel/tech-challenge/internal/chat/model"
-	"github.com/openai/openai-go/v2"
+	"github.com/acai-travel/tech-challenge/internal/llm"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.opentelemetry.io/otel"
 	"log/slog"
+	"os"
 	"strings"
+	"time"
 )
 
 var tracer = otel.Tracer("assistant")
 
+const (
+	defaultModel      = "gpt-4.1"
+	defaultTitleModel = "o1"
+)
+
 type Assistant struct {
-	cli             openai.Client
+	llm             llm.Provider
+	model           string
+	titleModel      string
 	registeredTools map[string]Tool
-	openaiTools     []openai.ChatCompletionToolUnionParam
+	tools           []llm.Tool
+	budgets         budgets
+	prices          llm.PriceTable
+
+	summaryThreshold int
 }
 
 type Tool interface {
 	Name() string
 	Description() string
-	Parameters() openai.FunctionParameters
+	Parameters() map[string]any
 	Execute(ctx context.Context, args ...string) (string, error)
 }
 
-func New() *Assistant {
+// New creates an assistant generating completions with the given provider and able to call the given tools. The
+// models used for replies and titles can be overridden with the LLM_MODEL and LLM_TITLE_MODEL environment variables,
+// the token budget of the history sent to them with LLM_TOKEN_BUDGET and LLM_TOKEN_BUDGETS, the number of messages
+// triggering a summary with LLM_SUMMARY_THRESHOLD, and the prices used to estimate costs with LLM_PRICES_FILE.
+func New(provider llm.Provider, usedTools ...Tool) *Assistant {
+	model := defaultModel
+	if v := os.Getenv("LLM_MODEL"); v != "" {
+		model = v
+	}
+
+	titleModel := defaultTitleModel
+	if v := os.Getenv("LLM_TITLE_MODEL"); v != "" {
+		titleModel = v
+	}
 
-	usedTools := []Tool{
-		&tools.WeatherTool{},
-		&tools.TodayTool{},
-		&tools.WeatherForecastTool{},
-		&tools.HolidaysTool{},
+	prices, err := llm.PricesFromEnv()
+	if err != nil {
+		slog.Warn("Failed to load the price table, using the default prices", "error", err)
 	}
 
-	openaiTools := []openai.ChatCompletionToolUnionParam{}
-	registeredTools := map[string]Tool{}
+	a := &Assistant{
+		llm:             provider,
+		model:           model,
+		titleModel:      titleModel,
+		registeredTools: map[string]Tool{},
+		budgets:         budgetsFromEnv(),
+		prices:          prices,
+
+		summaryThreshold: summaryThresholdFromEnv(),
+	}
 
 	for _, t := range usedTools {
-		registeredTools[t.Name()] = t
-		openaiTools = append(openaiTools, openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		a.registeredTools[t.Name()] = t
+		a.tools = append(a.tools, llm.Tool{
 			Name:        t.Name(),
-			Description: openai.String(t.Description()),
+			Description: t.Description(),
 			Parameters:  t.Parameters(),
-		}))
+		})
 	}
 
-	return &Assistant{
-		cli:             openai.NewClient(),
-		registeredTools: registeredTools,
-		openaiTools:     openaiTools,
-	}
+	return a
 }
 
-func (a *Assistant) Title(ctx context.Context, conv *model.Conversation) (string, error) {
-	ctx, span := tracer.Start(ctx, "Assistant.Reply")
+// Title generates a title for the conversation, and returns it with the usage of its generation.
+func (a *Assistant) Title(ctx context.Context, conv *model.Conversation) (string, *model.Usage, error) {
+	ctx, span := tracer.Start(ctx, "Assistant.Title")
 	defer span.End()
 
 	if len(conv.Messages) == 0 {
-		return "An empty conversation", nil
+		return "An empty conversation", nil, nil
 	}
 
 	slog.InfoContext(ctx, "Generating title for conversation", "conversation_id", conv.ID)
 
-	msgs := []openai.ChatCompletionMessageParamUnion{
-		openai.SystemMessage("Generate a concise, descriptive title for the conversation. It should reflect users intention based on the user message. The title should be a single line, no more than 80 characters, and should not include any special characters or emojis."),
+	msgs := []llm.Message{
+		{Role: llm.RoleSystem, Content: "Generate a concise, descriptive title for the conversation. It should reflect users intention based on the user message. The title should be a single line, no more than 80 characters, and should not include any special characters or emojis."},
 	}
 
 	for _, m := range conv.Messages {
-		msgs = append(msgs, openai.UserMessage(m.Content))
+		if m.Role == model.RoleUser {
+			msgs = append(msgs, llm.Message{Role: llm.RoleUser, Content: m.Content})
+		}
 	}
 
-	resp, err := a.cli.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model:    openai.ChatModelO1,
-		Messages: msgs,
-	})
-
+	resp, err := a.llm.Complete(ctx, llm.Request{Model: a.titleModel, Messages: msgs}, nil)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	if len(resp.Choices) == 0 || strings.TrimSpace(resp.Choices[0].Message.Content) == "" {
-		return "", errors.New("empty response from OpenAI for title generation")
+	_, usage := a.usage(ctx, a.titleModel, resp)
+
+	if strings.TrimSpace(resp.Message.Content) == "" {
+		return "", usage, errors.New("empty response from the model for title generation")
 	}
 
-	title := resp.Choices[0].Message.Content
+	title := resp.Message.Content
 	title = strings.ReplaceAll(title, "\n", " ")
 	title = strings.Trim(title, " \t\r\n-\"'")
 
@@ -95,69 +125,174 @@ func (a *Assistant) Title(ctx context.Context, conv *model.Conversation) (string
 
 	slog.InfoContext(ctx, "Generated title for conversation", "conversation_id", conv.ID, "title", title)
 
-	return title, nil
+	return title, usage, nil
 }
 
-func (a *Assistant) Reply(ctx context.Context, conv *model.Conversation) (string, error) {
+// Reply generates the assistant's answer to the conversation. It returns the new messages in order: the tool calls
+// and tool results produced along the way, followed by the final assistant message.
+func (a *Assistant) Reply(ctx context.Context, conv *model.Conversation) ([]*model.Message, error) {
 	ctx, span := tracer.Start(ctx, "Assistant.Reply")
 	defer span.End()
 
+	return a.reply(ctx, conv, nil)
+}
+
+// reply runs the tool loop until the model produces a final answer. Progress is reported to emit when it is set.
+func (a *Assistant) reply(ctx context.Context, conv *model.Conversation, emit func(Event)) ([]*model.Message, error) {
 	if len(conv.Messages) == 0 {
-		return "", errors.New("conversation has no messages")
+		return nil, errors.New("conversation has no messages")
 	}
 
 	slog.InfoContext(ctx, "Generating reply for conversation", "conversation_id", conv.ID)
 
-	msgs := []openai.ChatCompletionMessageParamUnion{
-		openai.SystemMessage("You are a helpful, concise AI assistant. Provide accurate, safe, and clear responses."),
-	}
-
-	for _, m := range conv.Messages {
-		switch m.Role {
-		case model.RoleUser:
-			msgs = append(msgs, openai.UserMessage(m.Content))
-		case model.RoleAssistant:
-			msgs = append(msgs, openai.AssistantMessage(m.Content))
+	var onDelta func(string)
+	if emit != nil {
+		onDelta = func(delta string) {
+			emit(Event{Type: EventDelta, Delta: delta})
 		}
+	} else {
+		emit = func(Event) {}
 	}
 
+	s := a.settings(ctx)
+	msgs := a.history(ctx, conv, s)
+	var generated []*model.Message
+
 	for i := 0; i < 15; i++ {
-		resp, err := a.cli.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-			Model:    openai.ChatModelGPT4_1,
+		resp, err := a.llm.Complete(ctx, llm.Request{
+			Model:    s.model,
 			Messages: msgs,
-			Tools:    a.openaiTools,
-		})
+			Tools:    s.tools,
+		}, onDelta)
 
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		if len(resp.Choices) == 0 {
-			return "", errors.New("no choices returned by OpenAI")
-		}
+		message := resp.Message
+		msgs = append(msgs, message)
 
-		if message := resp.Choices[0].Message; len(message.ToolCalls) > 0 {
-			msgs = append(msgs, message.ToParam())
+		request := newMessage(model.RoleAssistant, message.Content)
+		request.Model, request.Usage = a.usage(ctx, s.model, resp)
+		generated = append(generated, request)
 
-			for _, call := range message.ToolCalls {
-				slog.InfoContext(ctx, "Tool call received", "name", call.Function.Name, "args", call.Function.Arguments)
+		if len(message.ToolCalls) == 0 {
+			return generated, nil
+		}
 
-				if _, ok := a.registeredTools[call.Function.Name]; !ok {
-					return "", errors.New("unknown tool call: " + call.Function.Name)
-				} else {
-					slog.InfoContext(ctx, "Executing tool", "name", call.Function.Name)
+		for _, call := range message.ToolCalls {
+			request.ToolCalls = append(request.ToolCalls, &model.ToolCall{ID: call.ID, Name: call.Name, Arguments: call.Arguments})
+			emit(Event{Type: EventToolCallStarted, ToolID: call.ID, ToolName: call.Name, ToolArgs: call.Arguments})
 
-					// For the sake of simplicity, we ignore the error from tool execution here.
-					answer, _ := a.registeredTools[call.Function.Name].Execute(ctx, call.Function.Arguments)
-					msgs = append(msgs, openai.ToolMessage(answer, call.ID))
-				}
+			answer, err := a.execute(ctx, call)
+			if err != nil {
+				return nil, err
 			}
 
-			continue
+			emit(Event{Type: EventToolCallFinished, ToolID: call.ID, ToolName: call.Name})
+			msgs = append(msgs, llm.Message{Role: llm.RoleTool, Content: answer, ToolCallID: call.ID})
+
+			result := newMessage(model.RoleTool, answer)
+			result.ToolCallID = call.ID
+			result.ToolName = call.Name
+			generated = append(generated, result)
+		}
+	}
+
+	return nil, errors.New("too many tool calls, unable to generate reply")
+}
+
+// usage returns the model that generated the response, falling back to the requested one when the provider does not
+// report it, and the usage of the response with its estimated cost.
+func (a *Assistant) usage(ctx context.Context, requested string, resp *llm.Response) (string, *model.Usage) {
+	name := resp.Model
+	if name == "" {
+		name = requested
+	}
+
+	usage := &model.Usage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	}
+
+	if cost, ok := a.prices.Cost(name, resp.Usage); ok {
+		usage.Cost = cost
+	} else {
+		slog.WarnContext(ctx, "No price for model, its cost is not estimated", "model", name)
+	}
+
+	return name, usage
+}
+
+func newMessage(role model.Role, content string) *model.Message {
+	return &model.Message{
+		ID:        primitive.NewObjectID(),
+		Role:      role,
+		Content:   content,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// settings are the model, system prompt and tools used for a request.
+type settings struct {
+	model  string
+	prompt string
+	tools  []llm.Tool
+}
+
+// settings returns the default settings of the assistant, overridden by those of the tenant of the request.
+func (a *Assistant) settings(ctx context.Context) settings {
+	s := settings{model: a.model, prompt: systemPrompt, tools: a.tools}
+
+	t := tenant.FromContext(ctx)
+	if t == nil {
+		return s
+	}
+
+	if t.Model != "" {
+		s.model = t.Model
+	}
+
+	if t.SystemPrompt != "" {
+		s.prompt = t.SystemPrompt
+	}
+
+	if t.Tools != nil {
+		s.tools = nil
+		for _, tool := range a.tools {
+			if t.ToolEnabled(tool.Name) {
+				s.tools = append(s.tools, tool)
+			}
 		}
+	}
 
-		return resp.Choices[0].Message.Content, nil
+	return s
+}
+
+// ToolNames returns the names of the tools the assistant is able to call.
+func (a *Assistant) ToolNames() []string {
+	var names []string
+	for _, t := range a.tools {
+		names = append(names, t.Name)
 	}
 
-	return "", errors.New("too many tool calls, unable to generate reply")
+	return names
+}
+
+// execute runs the registered tool for the given call and returns its answer for the model.
+func (a *Assistant) execute(ctx context.Context, call llm.ToolCall) (string, error) {
+	slog.InfoContext(ctx, "Tool call received", "id", call.ID, "name", call.Name, "args", call.Arguments)
+
+	tool, ok := a.registeredTools[call.Name]
+	if t := tenant.FromContext(ctx); !ok || (t != nil && !t.ToolEnabled(call.Name)) {
+		return "", errors.New("unknown tool call: " + call.Name)
+	}
+
+	slog.InfoContext(ctx, "Executing tool", "name", call.Name)
+
+	// For the sake of simplicity, we ignore the error from tool execution here.
+	answer, _ := tool.Execute(ctx, call.Arguments)
+	return answer, nil
 }
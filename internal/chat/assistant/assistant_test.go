@@ -0,0 +1,312 @@
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/assistant/tools"
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/llm"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func conversation(content string) *model.Conversation {
+	return &model.Conversation{
+		ID:       primitive.NewObjectID(),
+		Messages: []*model.Message{{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: content}},
+	}
+}
+
+func TestAssistant_Title(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("title is trimmed to a single line", func(t *testing.T) {
+		fake := llm.NewFake(llm.Response{Message: llm.Message{Content: "\"Weather in\nBarcelona\"\n"}})
+
+		title, _, err := New(fake).Title(ctx, conversation("What is the weather like in Barcelona?"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if title != "Weather in Barcelona" {
+			t.Errorf("Title() = %q, want %q", title, "Weather in Barcelona")
+		}
+
+		if reqs := fake.Requests(); len(reqs) != 1 || reqs[0].Model != defaultTitleModel {
+			t.Errorf("expected a single request for model %q, got %+v", defaultTitleModel, reqs)
+		}
+	})
+
+	t.Run("empty title is an error", func(t *testing.T) {
+		fake := llm.NewFake(llm.Response{Message: llm.Message{Content: "  "}})
+
+		if _, _, err := New(fake).Title(ctx, conversation("Hi")); err == nil {
+			t.Fatal("expected error for empty title, got nil")
+		}
+	})
+}
+
+func TestAssistant_ReplyStream(t *testing.T) {
+	ctx := context.Background()
+
+	fake := llm.NewFake(
+		llm.Response{Message: llm.Message{ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "get_today_date", Arguments: "{}"}}}},
+		llm.Response{Message: llm.Message{Content: "Today is Monday."}},
+	)
+
+	var events []Event
+	messages, err := New(fake, &tools.TodayTool{}).ReplyStream(ctx, conversation("What day is today?"), func(e Event) {
+		events = append(events, e)
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The tool call and its result are returned along with the final reply, so they can be persisted.
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+
+	if m := messages[0]; m.Role != model.RoleAssistant || len(m.ToolCalls) != 1 || m.ToolCalls[0].Name != "get_today_date" {
+		t.Errorf("expected assistant tool call message, got %+v", m)
+	}
+
+	if m := messages[1]; m.Role != model.RoleTool || m.ToolCallID != "call_1" || m.ToolName != "get_today_date" || m.Content == "" {
+		t.Errorf("expected tool result message, got %+v", m)
+	}
+
+	if m := messages[2]; m.Role != model.RoleAssistant || m.Content != "Today is Monday." {
+		t.Errorf("expected final reply %q, got %+v", "Today is Monday.", m)
+	}
+
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+
+	want := []EventType{EventToolCallStarted, EventToolCallFinished, EventDelta, EventDelta, EventDelta}
+	if len(types) != len(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("events = %v, want %v", types, want)
+		}
+	}
+
+	// The second completion must include the tool call and its answer.
+	reqs := fake.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 completion requests, got %d", len(reqs))
+	}
+
+	last := reqs[1].Messages[len(reqs[1].Messages)-1]
+	if last.Role != llm.RoleTool || last.ToolCallID != "call_1" || last.Content == "" {
+		t.Errorf("expected tool answer for call_1 as last message, got %+v", last)
+	}
+}
+
+func TestAssistant_Reply(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("tool calls and results are replayed to the model", func(t *testing.T) {
+		conv := conversation("What is the weather like in Oslo?")
+		conv.Messages = append(conv.Messages,
+			&model.Message{Role: model.RoleAssistant, ToolCalls: []*model.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Oslo"}`}}},
+			&model.Message{Role: model.RoleTool, ToolCallID: "call_1", ToolName: "get_weather", Content: `{"temperature":"-3.0 °C"}`},
+			&model.Message{Role: model.RoleAssistant, Content: "It is -3 °C in Oslo."},
+			&model.Message{Role: model.RoleUser, Content: "Is that below freezing?"},
+		)
+
+		fake := llm.NewFake(llm.Response{Message: llm.Message{Content: "Yes."}})
+		if _, err := New(fake).Reply(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		msgs := fake.Requests()[0].Messages
+		if len(msgs) != 6 {
+			t.Fatalf("expected system prompt and 5 messages, got %d", len(msgs))
+		}
+
+		if call := msgs[2]; call.Role != llm.RoleAssistant || len(call.ToolCalls) != 1 || call.ToolCalls[0].ID != "call_1" {
+			t.Errorf("expected replayed tool call, got %+v", call)
+		}
+
+		if result := msgs[3]; result.Role != llm.RoleTool || result.ToolCallID != "call_1" || result.Content == "" {
+			t.Errorf("expected replayed tool result, got %+v", result)
+		}
+	})
+
+	t.Run("assistant messages record the model and the usage with its cost", func(t *testing.T) {
+		fake := llm.NewFake(
+			llm.Response{Message: llm.Message{ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "get_today_date", Arguments: "{}"}}}},
+			llm.Response{
+				Message: llm.Message{Content: "Today is Monday."},
+				Model:   "gpt-4.1-2025-04-14",
+				Usage:   llm.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500},
+			},
+		)
+
+		messages, err := New(fake, &tools.TodayTool{}).Reply(ctx, conversation("What day is today?"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if call := messages[0]; call.Model != defaultModel || call.Usage == nil || call.Usage.TotalTokens == 0 {
+			t.Errorf("expected tool call with model %q and usage, got %+v", defaultModel, call)
+		}
+
+		if result := messages[1]; result.Model != "" || result.Usage != nil {
+			t.Errorf("expected tool result without usage, got %+v", result)
+		}
+
+		// 1000 prompt tokens at $2 and 500 completion tokens at $8 per million.
+		want := model.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500, Cost: 0.006}
+		if answer := messages[2]; answer.Model != "gpt-4.1-2025-04-14" || answer.Usage == nil || *answer.Usage != want {
+			t.Errorf("expected answer with usage %+v, got %+v", want, answer)
+		}
+	})
+
+	t.Run("tenant settings override the defaults", func(t *testing.T) {
+		ctx := tenant.WithTenant(ctx, &tenant.Tenant{
+			ID:           "sunny",
+			SystemPrompt: "You are Sunny, the travel assistant of Sunny Trips.",
+			Tools:        []string{"get_today_date"},
+			Model:        "gpt-4o-mini",
+		})
+
+		fake := llm.NewFake(
+			llm.Response{Message: llm.Message{ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: "{}"}}}},
+		)
+
+		_, err := New(fake, &tools.TodayTool{}, tools.NewWeatherTool(nil)).Reply(ctx, conversation("What is the weather like?"))
+		if err == nil {
+			t.Fatal("expected error for a tool disabled for the tenant, got nil")
+		}
+
+		req := fake.Requests()[0]
+		if req.Model != "gpt-4o-mini" || req.Messages[0].Content != "You are Sunny, the travel assistant of Sunny Trips." {
+			t.Errorf("expected tenant model and system prompt, got %q and %q", req.Model, req.Messages[0].Content)
+		}
+
+		if len(req.Tools) != 1 || req.Tools[0].Name != "get_today_date" {
+			t.Errorf("expected only the tenant tools, got %+v", req.Tools)
+		}
+	})
+
+	t.Run("older turns are dropped to fit the token budget, pinned ones are kept", func(t *testing.T) {
+		t.Setenv("LLM_TOKEN_BUDGET", "200")
+
+		conv := conversation("Remember that my name is Ada.")
+		conv.Messages[0].Pinned = true
+		conv.Messages = append(conv.Messages, &model.Message{Role: model.RoleAssistant, Content: "Noted."})
+
+		for i := 0; i < 4; i++ {
+			conv.Messages = append(conv.Messages,
+				&model.Message{Role: model.RoleUser, Content: fmt.Sprintf("%d %s", i, strings.Repeat("a", 400))},
+				&model.Message{Role: model.RoleAssistant, Content: "ok"},
+			)
+		}
+
+		conv.Messages = append(conv.Messages, &model.Message{Role: model.RoleUser, Content: "What is my name?"})
+
+		fake := llm.NewFake(llm.Response{Message: llm.Message{Content: "Ada."}})
+		if _, err := New(fake).Reply(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []string
+		for _, m := range fake.Requests()[0].Messages[1:] {
+			got = append(got, m.Content[:min(len(m.Content), 6)])
+		}
+
+		// The system prompt, the pinned turn, the most recent turn that fits, and the message to answer.
+		want := []string{"Rememb", "Noted.", "3 aaaa", "ok", "What i"}
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("history = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestAssistant_Summarize(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("LLM_SUMMARY_THRESHOLD", "2")
+
+	conv := conversation("I'm travelling to Rome with a toddler.")
+	conv.Messages = append(conv.Messages,
+		&model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "Great, Rome is lovely with kids."},
+		&model.Message{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: "What is the weather like?"},
+	)
+
+	t.Run("no summary below the threshold", func(t *testing.T) {
+		fake := llm.NewFake()
+
+		summary, err := New(fake).Summarize(ctx, conv)
+		if err != nil || summary != nil {
+			t.Fatalf("Summarize() = %+v, %v, want nil", summary, err)
+		}
+
+		if len(fake.Requests()) != 0 {
+			t.Errorf("expected no completion request, got %d", len(fake.Requests()))
+		}
+	})
+
+	t.Run("summary covers the messages before the latest turns", func(t *testing.T) {
+		long := *conv
+		long.Messages = append(long.Messages,
+			&model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "Sunny."},
+			&model.Message{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: "And tomorrow?"},
+		)
+
+		fake := llm.NewFake(llm.Response{Message: llm.Message{Content: "- Travelling to Rome with a toddler."}})
+
+		summary, err := New(fake).Summarize(ctx, &long)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if summary == nil || summary.Text != "- Travelling to Rome with a toddler." || summary.Through != long.Messages[1].ID {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+
+		if req := fake.Requests()[0].Messages[1].Content; !strings.Contains(req, "toddler") || strings.Contains(req, "tomorrow") {
+			t.Errorf("unexpected summary request: %q", req)
+		}
+	})
+
+	t.Run("summary replaces dropped turns", func(t *testing.T) {
+		t.Setenv("LLM_TOKEN_BUDGET", "100")
+
+		long := *conv
+		long.Summary = &model.Summary{Text: "- Travelling with a toddler.", Through: conv.Messages[1].ID}
+		long.Messages = append([]*model.Message{
+			{Role: model.RoleUser, Content: strings.Repeat("a", 400)},
+			{Role: model.RoleAssistant, Content: "ok"},
+		}, long.Messages...)
+
+		fake := llm.NewFake(llm.Response{Message: llm.Message{Content: "Sunny."}})
+		if _, err := New(fake).Reply(ctx, &long); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		msgs := fake.Requests()[0].Messages
+		if msgs[1].Role != llm.RoleSystem || !strings.Contains(msgs[1].Content, "toddler") {
+			t.Errorf("expected summary after the system prompt, got %+v", msgs[1])
+		}
+
+		if last := msgs[len(msgs)-1]; last.Content != "What is the weather like?" {
+			t.Errorf("expected the latest message last, got %+v", last)
+		}
+
+		for _, m := range msgs {
+			if strings.HasPrefix(m.Content, "aaaa") {
+				t.Errorf("expected the oldest turn to be dropped, got %d messages", len(msgs))
+			}
+		}
+	})
+}
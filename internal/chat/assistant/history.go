@@ -0,0 +1,177 @@
+package assistant
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/llm"
+)
+
+const (
+	systemPrompt = "You are a helpful, concise AI assistant. Provide accurate, safe, and clear responses."
+
+	// defaultTokenBudget is the number of prompt tokens a request may take, unless configured for its model.
+	defaultTokenBudget = 32000
+)
+
+// budgets holds the number of prompt tokens requests to each model may take.
+type budgets struct {
+	fallback int
+	models   map[string]int
+}
+
+// budgetsFromEnv reads the default budget from LLM_TOKEN_BUDGET, and per model budgets from LLM_TOKEN_BUDGETS as a
+// comma separated list of model=tokens pairs, e.g. "gpt-4.1=100000,gpt-4o-mini=16000".
+func budgetsFromEnv() budgets {
+	b := budgets{fallback: defaultTokenBudget, models: map[string]int{}}
+
+	if v := os.Getenv("LLM_TOKEN_BUDGET"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			b.fallback = n
+		} else {
+			slog.Warn("Ignoring invalid LLM_TOKEN_BUDGET", "value", v)
+		}
+	}
+
+	for _, pair := range strings.Split(os.Getenv("LLM_TOKEN_BUDGETS"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		name, tokens, _ := strings.Cut(pair, "=")
+		if n, err := strconv.Atoi(strings.TrimSpace(tokens)); err == nil && n > 0 {
+			b.models[strings.TrimSpace(name)] = n
+		} else {
+			slog.Warn("Ignoring invalid LLM_TOKEN_BUDGETS entry", "entry", pair)
+		}
+	}
+
+	return b
+}
+
+func (b budgets) forModel(model string) int {
+	if n, ok := b.models[model]; ok {
+		return n
+	}
+
+	return b.fallback
+}
+
+// turn is a user message followed by the assistant and tool messages answering it.
+type turn struct {
+	messages []llm.Message
+	tokens   int
+	pinned   bool
+}
+
+// history converts the conversation into the message list sent to the model, prefixed with the system prompt. Tool
+// calls and results from previous turns are replayed, so the model can refer to the data it already fetched.
+//
+// Older turns are dropped to keep the request within the token budget of the model, and replaced by the conversation
+// summary when there is one. The latest turn, which holds the message to answer, is always kept, as are the turns with
+// pinned or system messages when they fit. Whole turns are dropped so that tool calls are never separated from their
+// results.
+func (a *Assistant) history(ctx context.Context, conv *model.Conversation, s settings) []llm.Message {
+	msgs := []llm.Message{{Role: llm.RoleSystem, Content: s.prompt}}
+
+	turns := splitTurns(conv, s.model)
+	if len(turns) == 0 {
+		return msgs
+	}
+
+	budget := a.budgets.forModel(s.model) - llm.CountTokens(s.model, msgs[0]) - llm.CountToolTokens(s.model, s.tools...)
+
+	total := 0
+	for _, t := range turns {
+		total += t.tokens
+	}
+
+	if total > budget && conv.Summary != nil {
+		summary := llm.Message{Role: llm.RoleSystem, Content: "Summary of the earlier conversation:\n" + conv.Summary.Text}
+		budget -= llm.CountTokens(s.model, summary)
+		msgs = append(msgs, summary)
+	}
+
+	last := len(turns) - 1
+	keep := make([]bool, len(turns))
+	keep[last] = true
+	budget -= turns[last].tokens
+
+	for i := last - 1; i >= 0; i-- {
+		if turns[i].pinned && turns[i].tokens <= budget {
+			keep[i] = true
+			budget -= turns[i].tokens
+		}
+	}
+
+	// Recent turns are kept until the first one that does not fit, so the kept history has no gaps.
+	for i := last - 1; i >= 0; i-- {
+		if keep[i] || turns[i].pinned {
+			continue
+		}
+
+		if turns[i].tokens > budget {
+			break
+		}
+
+		keep[i] = true
+		budget -= turns[i].tokens
+	}
+
+	dropped := 0
+
+	for i, t := range turns {
+		if keep[i] {
+			msgs = append(msgs, t.messages...)
+		} else {
+			dropped += len(t.messages)
+		}
+	}
+
+	if dropped > 0 {
+		slog.InfoContext(ctx, "Dropped older messages to fit the token budget", "conversation_id", conv.ID, "dropped", dropped, "model", s.model)
+	}
+
+	return msgs
+}
+
+// splitTurns splits the conversation into turns, counting their tokens for the model. Messages preceding the first
+// user message form a turn of their own.
+func splitTurns(conv *model.Conversation, name string) []turn {
+	var turns []turn
+
+	for _, m := range conv.Messages {
+		var msg llm.Message
+
+		switch m.Role {
+		case model.RoleUser:
+			msg = llm.Message{Role: llm.RoleUser, Content: m.Content}
+		case model.RoleSystem:
+			msg = llm.Message{Role: llm.RoleSystem, Content: m.Content}
+		case model.RoleAssistant:
+			msg = llm.Message{Role: llm.RoleAssistant, Content: m.Content}
+			for _, c := range m.ToolCalls {
+				msg.ToolCalls = append(msg.ToolCalls, llm.ToolCall{ID: c.ID, Name: c.Name, Arguments: c.Arguments})
+			}
+		case model.RoleTool:
+			msg = llm.Message{Role: llm.RoleTool, Content: m.Content, ToolCallID: m.ToolCallID}
+		default:
+			continue
+		}
+
+		if m.Role == model.RoleUser || len(turns) == 0 {
+			turns = append(turns, turn{})
+		}
+
+		t := &turns[len(turns)-1]
+		t.messages = append(t.messages, msg)
+		t.tokens += llm.CountTokens(name, msg)
+		t.pinned = t.pinned || m.Pinned || m.Role == model.RoleSystem
+	}
+
+	return turns
+}
@@ -0,0 +1,36 @@
+package assistant
+
+import (
+	"context"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+)
+
+type EventType string
+
+const (
+	// EventDelta carries a chunk of the reply text as soon as the model produces it.
+	EventDelta EventType = "delta"
+	// EventToolCallStarted is emitted right before a tool is executed.
+	EventToolCallStarted EventType = "tool_call_started"
+	// EventToolCallFinished is emitted once a tool has produced its answer.
+	EventToolCallFinished EventType = "tool_call_finished"
+)
+
+// Event is a single progress notification emitted by ReplyStream while the reply is being generated.
+type Event struct {
+	Type     EventType `json:"type"`
+	Delta    string    `json:"delta,omitempty"`
+	ToolID   string    `json:"tool_call_id,omitempty"`
+	ToolName string    `json:"tool_name,omitempty"`
+	ToolArgs string    `json:"tool_arguments,omitempty"`
+}
+
+// ReplyStream works like Reply, but streams the completions and reports token deltas and tool calls to emit while
+// the reply is being generated. The new messages are returned once the model has finished.
+func (a *Assistant) ReplyStream(ctx context.Context, conv *model.Conversation, emit func(Event)) ([]*model.Message, error) {
+	ctx, span := tracer.Start(ctx, "Assistant.ReplyStream")
+	defer span.End()
+
+	return a.reply(ctx, conv, emit)
+}
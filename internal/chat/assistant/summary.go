@@ -0,0 +1,126 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/llm"
+)
+
+const (
+	// defaultSummaryThreshold is the number of unsummarized messages that triggers a new summary.
+	defaultSummaryThreshold = 20
+
+	// recentTurns is the number of latest turns left out of summaries, as they are usually sent in full.
+	recentTurns = 2
+
+	// maxSummarizedToolResult is the number of characters of a tool result included in the summary request.
+	maxSummarizedToolResult = 500
+
+	summaryPrompt = "Summarize the conversation between a user and a travel assistant, to be used as the assistant's memory once the messages are gone. Start from the existing summary, if any, and update it with the new messages. Keep every fact about the user and their plans, such as destinations, dates, travel companions, preferences and constraints, and the answers they were given. Drop small talk. Reply with the summary only, as short bullet points."
+)
+
+// summaryThresholdFromEnv reads the summary threshold from LLM_SUMMARY_THRESHOLD, where 0 disables summaries.
+func summaryThresholdFromEnv() int {
+	v := os.Getenv("LLM_SUMMARY_THRESHOLD")
+	if v == "" {
+		return defaultSummaryThreshold
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		slog.Warn("Ignoring invalid LLM_SUMMARY_THRESHOLD", "value", v)
+		return defaultSummaryThreshold
+	}
+
+	return n
+}
+
+// Summarize returns an updated summary of the conversation once enough messages were added since the last one, and
+// nil otherwise. The latest turns are left out, they are summarized once the conversation moves on.
+func (a *Assistant) Summarize(ctx context.Context, conv *model.Conversation) (*model.Summary, error) {
+	ctx, span := tracer.Start(ctx, "Assistant.Summarize")
+	defer span.End()
+
+	pending := unsummarized(conv)
+	if a.summaryThreshold == 0 || len(pending) < a.summaryThreshold {
+		return nil, nil
+	}
+
+	slog.InfoContext(ctx, "Summarizing conversation", "conversation_id", conv.ID, "messages", len(pending))
+
+	var b strings.Builder
+	if conv.Summary != nil {
+		fmt.Fprintf(&b, "Existing summary:\n%s\n\n", conv.Summary.Text)
+	}
+
+	b.WriteString("New messages:\n")
+	for _, m := range pending {
+		switch m.Role {
+		case model.RoleUser, model.RoleAssistant:
+			if m.Content != "" {
+				fmt.Fprintf(&b, "%s: %s\n", m.Role, m.Content)
+			}
+			for _, c := range m.ToolCalls {
+				fmt.Fprintf(&b, "%s called %s with %s\n", m.Role, c.Name, c.Arguments)
+			}
+		case model.RoleTool:
+			content := []rune(m.Content)
+			fmt.Fprintf(&b, "%s answered: %s\n", m.ToolName, string(content[:min(len(content), maxSummarizedToolResult)]))
+		}
+	}
+
+	s := a.settings(ctx)
+
+	resp, err := a.llm.Complete(ctx, llm.Request{
+		Model: s.model,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: summaryPrompt},
+			{Role: llm.RoleUser, Content: b.String()},
+		},
+	}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(resp.Message.Content)
+	if text == "" {
+		return nil, errors.New("empty response from the model for summary generation")
+	}
+
+	_, usage := a.usage(ctx, s.model, resp)
+	return &model.Summary{Text: text, Through: pending[len(pending)-1].ID, UpdatedAt: time.Now(), Usage: usage}, nil
+}
+
+// unsummarized returns the messages following the summary of the conversation, without the latest turns.
+func unsummarized(conv *model.Conversation) []*model.Message {
+	start := 0
+	if conv.Summary != nil {
+		for i, m := range conv.Messages {
+			if m.ID == conv.Summary.Through {
+				start = i + 1
+			}
+		}
+	}
+
+	users := 0
+	for i := len(conv.Messages) - 1; i >= start; i-- {
+		if conv.Messages[i].Role != model.RoleUser {
+			continue
+		}
+
+		if users++; users == recentTurns {
+			return conv.Messages[start:i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/acai-travel/tech-challenge/internal/weather"
+	"log/slog"
+)
+
+// usEPACategories maps the US-EPA index (1-6) to its category.
+var usEPACategories = []string{
+	1: "Good",
+	2: "Moderate",
+	3: "Unhealthy for sensitive groups",
+	4: "Unhealthy",
+	5: "Very unhealthy",
+	6: "Hazardous",
+}
+
+type AirQualityTool struct {
+	client *weather.Client
+}
+
+func NewAirQualityTool(client *weather.Client) *AirQualityTool {
+	return &AirQualityTool{client: client}
+}
+
+func (a *AirQualityTool) Name() string {
+	return "get_air_quality"
+}
+
+func (a *AirQualityTool) Description() string {
+	return "Get the current air quality at the given location as JSON, with the US-EPA index and its category, and PM2.5, PM10, O3, NO2, CO and SO2 concentrations in μg/m3."
+}
+
+func (a *AirQualityTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]string{
+				"type":        "string",
+				"description": "Given location",
+			},
+		},
+		"required": []string{"location"},
+	}
+}
+
+// airQuality is the summary of the air quality returned to the model.
+type airQuality struct {
+	Location   string  `json:"location"`
+	LocalTime  string  `json:"local_time"`
+	USEPAIndex int     `json:"us_epa_index"`
+	Category   string  `json:"category"`
+	PM2_5      float64 `json:"pm2_5"`
+	PM10       float64 `json:"pm10"`
+	O3         float64 `json:"o3"`
+	NO2        float64 `json:"no2"`
+	CO         float64 `json:"co"`
+	SO2        float64 `json:"so2"`
+}
+
+func (a *AirQualityTool) Execute(ctx context.Context, args ...string) (string, error) {
+
+	var parameters struct {
+		Location string `json:"location"`
+	}
+
+	if err := json.Unmarshal([]byte(args[0]), &parameters); err != nil {
+		return "failed to parse tool call arguments: " + err.Error(), nil
+	}
+
+	slog.InfoContext(ctx, "Executing AirQualityTool", "location", parameters.Location)
+
+	resp, err := a.client.GetCurrentWeather(ctx, parameters.Location, true)
+	if err != nil {
+		// Return error as a user-friendly message
+		return "Air quality service error: " + err.Error(), nil
+	}
+
+	aq := resp.Current.AirQuality
+	if aq == nil {
+		return "No air quality data available for " + resp.Location.Name + ".", nil
+	}
+
+	summary := airQuality{
+		Location:   resp.Location.Name + ", " + resp.Location.Country,
+		LocalTime:  resp.Location.Localtime,
+		USEPAIndex: aq.USEPAIndex,
+		PM2_5:      aq.PM2_5,
+		PM10:       aq.PM10,
+		O3:         aq.O3,
+		NO2:        aq.NO2,
+		CO:         aq.CO,
+		SO2:        aq.SO2,
+	}
+
+	if aq.USEPAIndex > 0 && aq.USEPAIndex < len(usEPACategories) {
+		summary.Category = usEPACategories[aq.USEPAIndex]
+	}
+
+	out, err := json.Marshal(summary)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
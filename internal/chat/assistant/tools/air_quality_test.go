@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAirQualityTool(t *testing.T) {
+	ctx := context.Background()
+
+	const location = `"location": {"name": "Madrid", "country": "Spain", "localtime": "2025-07-01 12:45"}`
+
+	t.Run("air quality with its category", func(t *testing.T) {
+		client := weatherClient(t, `{`+location+`, "current": {"air_quality": {
+			"co": 258.1, "no2": 14.615, "o3": 148, "so2": 4.44, "pm2_5": 12.395, "pm10": 15.725, "us-epa-index": 4, "gb-defra-index": 7
+		}}}`, func(path string, query url.Values) {
+			if query.Get("aqi") != "yes" {
+				t.Errorf("expected air quality to be requested, got %s", query.Encode())
+			}
+		})
+
+		out, err := NewAirQualityTool(client).Execute(ctx, `{"location": "Madrid"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got airQuality
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("expected JSON air quality, got %q", out)
+		}
+
+		want := airQuality{
+			Location: "Madrid, Spain", LocalTime: "2025-07-01 12:45", USEPAIndex: 4, Category: "Unhealthy",
+			PM2_5: 12.395, PM10: 15.725, O3: 148, NO2: 14.615, CO: 258.1, SO2: 4.44,
+		}
+
+		if !cmp.Equal(got, want) {
+			t.Errorf("air quality mismatch (-got +want):\n%s", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("unknown index has no category", func(t *testing.T) {
+		client := weatherClient(t, `{`+location+`, "current": {"air_quality": {"us-epa-index": 9}}}`, nil)
+
+		out, err := NewAirQualityTool(client).Execute(ctx, `{"location": "Madrid"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got airQuality
+		if err := json.Unmarshal([]byte(out), &got); err != nil || got.USEPAIndex != 9 || got.Category != "" {
+			t.Errorf("expected index 9 without category, got %q", out)
+		}
+	})
+
+	t.Run("missing air quality is reported to the model", func(t *testing.T) {
+		client := weatherClient(t, `{`+location+`, "current": {"temp_c": 33.2}}`, nil)
+
+		out, err := NewAirQualityTool(client).Execute(ctx, `{"location": "Madrid"}`)
+		if err != nil || out != "No air quality data available for Madrid." {
+			t.Errorf("expected no air quality message, got %q, err %v", out, err)
+		}
+	})
+}
@@ -4,98 +4,228 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	ics "github.com/arran4/golang-ical"
-	"github.com/openai/openai-go/v2"
+	"github.com/acai-travel/tech-challenge/internal/holidays"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
 	"log/slog"
-	"os"
 	"strings"
 	"time"
 )
 
-func LoadCalendar(ctx context.Context, link string) ([]*ics.VEvent, error) {
-	slog.InfoContext(ctx, "Loading calendar", "link", link)
-
-	cal, err := ics.ParseCalendarFromUrl(link, ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse calendar: %w", err)
-	}
-
-	return cal.Events(), nil
+type HolidaysTool struct {
+	calendars *holidays.Service
+	registry  *holidays.Registry
+	now       func() time.Time
 }
 
-type HolidaysTool struct{}
+func NewHolidaysTool(calendars *holidays.Service, registry *holidays.Registry) *HolidaysTool {
+	return &HolidaysTool{calendars: calendars, registry: registry, now: time.Now}
+}
 
 func (h *HolidaysTool) Name() string {
 	return "get_holidays"
 }
 
 func (h *HolidaysTool) Description() string {
-	return "Gets local bank and public holidays. Each line is a single holiday in the format 'YYYY-MM-DD: Holiday Name'."
+	return "Gets local bank and public holidays of a country or region, by default the user's, sorted by date as JSON. " +
+		"Each holiday has its weekday, whether it makes a long weekend, and the bridge day to take off to make one."
 }
 
-func (h *HolidaysTool) Parameters() openai.FunctionParameters {
-	return openai.FunctionParameters{
+func (h *HolidaysTool) Parameters() map[string]any {
+	return map[string]any{
 		"type": "object",
 		"properties": map[string]any{
-			"before_date": map[string]string{
+			"country": map[string]string{
+				"type":        "string",
+				"description": "Optional ISO 3166-1 alpha-2 country code, e.g. 'PT' for Lisbon or 'DE' for Munich. If not provided, the user's default calendar is used.",
+			},
+			"region": map[string]string{
 				"type":        "string",
-				"description": "Optional date in RFC3339 format to get holidays before this date. If not provided, all holidays will be returned.",
+				"description": "Optional ISO 3166-2 region code, e.g. 'ES-CT' for Catalonia or 'DE-BY' for Bavaria, to include regional holidays.",
 			},
 			"after_date": map[string]string{
 				"type":        "string",
-				"description": "Optional date in RFC3339 format to get holidays after this date. If not provided, all holidays will be returned.",
+				"description": "Optional first date, inclusive, in 'YYYY-MM-DD' or RFC3339 format. Defaults to today.",
+			},
+			"before_date": map[string]string{
+				"type":        "string",
+				"description": "Optional last date, inclusive, in 'YYYY-MM-DD' or RFC3339 format. Defaults to a year after after_date.",
 			},
 			"max_count": map[string]string{
 				"type":        "integer",
-				"description": "Optional maximum number of holidays to return. If not provided, all holidays will be returned.",
+				"description": "Optional maximum number of holidays to return, the earliest first. If not provided, all holidays will be returned.",
 			},
 		},
 	}
+}
+
+// holiday is a holiday as returned to the model.
+type holiday struct {
+	Date    string `json:"date"`
+	EndDate string `json:"end_date,omitempty"`
+	Weekday string `json:"weekday"`
+	Name    string `json:"name"`
+	Region  string `json:"region"`
+
+	// DaysOff is the number of consecutive days off, weekends included, the holiday is part of.
+	DaysOff     int  `json:"days_off"`
+	LongWeekend bool `json:"long_weekend"`
 
+	// BridgeDay is a working day which, taken off, joins the holiday to a weekend.
+	BridgeDay string `json:"bridge_day,omitempty"`
 }
 
 func (h *HolidaysTool) Execute(ctx context.Context, args ...string) (string, error) {
-	link := "https://www.officeholidays.com/ics/spain/catalonia"
-	if v := os.Getenv("HOLIDAY_CALENDAR_LINK"); v != "" {
-		link = v
+	var payload struct {
+		Country    string `json:"country,omitempty"`
+		Region     string `json:"region,omitempty"`
+		BeforeDate string `json:"before_date,omitempty"`
+		AfterDate  string `json:"after_date,omitempty"`
+		MaxCount   int    `json:"max_count,omitempty"`
 	}
 
-	events, err := LoadCalendar(ctx, link)
+	if err := json.Unmarshal([]byte(args[0]), &payload); err != nil {
+		return "failed to parse tool call arguments: " + err.Error(), nil
+	}
+
+	from, err := parseDate(payload.AfterDate, h.now())
 	if err != nil {
-		return "failed to load holiday events", nil
+		return "invalid after_date: " + err.Error(), nil
 	}
 
-	var payload struct {
-		BeforeDate time.Time `json:"before_date,omitempty"`
-		AfterDate  time.Time `json:"after_date,omitempty"`
-		MaxCount   int       `json:"max_count,omitempty"`
+	to, err := parseDate(payload.BeforeDate, from.AddDate(1, 0, 0))
+	if err != nil {
+		return "invalid before_date: " + err.Error(), nil
 	}
 
-	if err := json.Unmarshal([]byte(args[0]), &payload); err != nil {
-		return "failed to parse tool call arguments: " + err.Error(), nil
+	if to.Before(from) {
+		return "before_date must not be before after_date", nil
+	}
+
+	calendar, exact, err := h.calendar(ctx, payload.Country, payload.Region)
+	if err != nil {
+		return err.Error(), nil
 	}
 
-	var holidays []string
-	for _, event := range events {
-		date, err := event.GetAllDayStartAt()
-		if err != nil {
-			continue
+	events, err := h.calendars.Events(ctx, calendar.Link())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load holiday events", "calendar", calendar.Code, "error", err)
+		return "failed to load holiday events", nil
+	}
+
+	// Holidays around the window are days off too, joining those of the window to weekends.
+	daysOff := map[time.Time]bool{}
+	for _, o := range holidays.Occurrences(events, from.AddDate(0, 0, -7), to.AddDate(0, 0, 7)) {
+		for d := o.Start; !d.After(o.End); d = d.AddDate(0, 0, 1) {
+			daysOff[d] = true
 		}
+	}
+
+	off := func(d time.Time) bool {
+		return daysOff[d] || d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+	}
 
-		if payload.MaxCount > 0 && len(holidays) >= payload.MaxCount {
+	out := []holiday{}
+	for _, o := range holidays.Occurrences(events, from, to) {
+		if payload.MaxCount > 0 && len(out) >= payload.MaxCount {
 			break
 		}
 
-		if !payload.BeforeDate.IsZero() && date.After(payload.BeforeDate) {
-			continue
+		out = append(out, newHoliday(o, calendar.Name, off))
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+
+	if !exact {
+		// The calendar is the one of the country, whose code qualifies a region given without it.
+		region := strings.ToUpper(strings.TrimSpace(payload.Region))
+		if !strings.Contains(region, "-") {
+			region = calendar.Code + "-" + region
+		}
+
+		return fmt.Sprintf("There is no holiday calendar for region %s, its regional holidays are not covered. National holidays of %s: %s", region, calendar.Name, b), nil
+	}
+
+	return string(b), nil
+}
+
+// newHoliday describes the holiday, given the days off of its calendar.
+func newHoliday(o holidays.Holiday, region string, off func(time.Time) bool) holiday {
+	h := holiday{
+		Date:    o.Start.Format(time.DateOnly),
+		Weekday: o.Start.Weekday().String(),
+		Name:    o.Name,
+		Region:  region,
+	}
+
+	if o.Days() > 1 {
+		h.EndDate = o.End.Format(time.DateOnly)
+	}
+
+	// The stretch of days off is widened on both sides, up to the first working days.
+	first, last := o.Start, o.End
+	for off(first.AddDate(0, 0, -1)) {
+		first = first.AddDate(0, 0, -1)
+	}
+	for off(last.AddDate(0, 0, 1)) {
+		last = last.AddDate(0, 0, 1)
+	}
+
+	h.DaysOff = holidays.Holiday{Start: first, End: last}.Days()
+
+	weekend := false
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			weekend = true
 		}
+	}
+	h.LongWeekend = weekend && h.DaysOff >= 3
+
+	// A single working day between the stretch and another day off is a bridge, e.g. the Friday after a Thursday.
+	if before := first.AddDate(0, 0, -1); off(before.AddDate(0, 0, -1)) {
+		h.BridgeDay = before.Format(time.DateOnly)
+	} else if after := last.AddDate(0, 0, 1); off(after.AddDate(0, 0, 1)) {
+		h.BridgeDay = after.Format(time.DateOnly)
+	}
+
+	return h
+}
+
+// parseDate parses a day given as a date or an RFC3339 time, def being used when it is empty.
+func parseDate(v string, def time.Time) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return holidays.Date(def), nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected 'YYYY-MM-DD' or RFC3339, got %q", v)
+	}
+
+	return holidays.Date(t), nil
+}
+
+// calendar returns the calendar of the requested country or region, or else the calendar of the tenant, or else the
+// default one. exact is false when the calendar of the country is returned for a region without one.
+func (h *HolidaysTool) calendar(ctx context.Context, country, region string) (c *holidays.Calendar, exact bool, err error) {
+	if country != "" || region != "" {
+		return h.registry.Lookup(country, region)
+	}
 
-		if !payload.AfterDate.IsZero() && date.Before(payload.AfterDate) {
-			continue
+	if t := tenant.FromContext(ctx); t != nil && t.HolidayCalendarLink != "" {
+		if c, ok := h.registry.ByLink(t.HolidayCalendarLink); ok {
+			return c, true, nil
 		}
 
-		holidays = append(holidays, date.Format(time.DateOnly)+": "+event.GetProperty(ics.ComponentPropertySummary).Value)
+		return &holidays.Calendar{Code: t.ID, Name: "local", URL: t.HolidayCalendarLink}, true, nil
 	}
 
-	return strings.Join(holidays, "\n"), nil
+	return h.registry.Lookup("", "")
 }
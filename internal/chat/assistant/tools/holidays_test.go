@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acai-travel/tech-challenge/internal/holidays"
+	"github.com/google/go-cmp/cmp"
+)
+
+// holidaysCalendar lists its events out of order, as calendars are not sorted.
+const holidaysCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//acai//holidays//EN
+BEGIN:VEVENT
+UID:christmas
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20261225
+SUMMARY:Christmas Day
+END:VEVENT
+BEGIN:VEVENT
+UID:new-year
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20260101
+SUMMARY:New Year's Day
+END:VEVENT
+BEGIN:VEVENT
+UID:new-year-2027
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20270101
+SUMMARY:New Year's Day
+END:VEVENT
+BEGIN:VEVENT
+UID:national-day
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20261001
+SUMMARY:National Day
+END:VEVENT
+BEGIN:VEVENT
+UID:easter-monday
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20260406
+SUMMARY:Easter Monday
+END:VEVENT
+BEGIN:VEVENT
+UID:assumption
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20260815
+SUMMARY:Assumption Day
+END:VEVENT
+BEGIN:VEVENT
+UID:st-john
+DTSTAMP:20260101T000000Z
+DTSTART;VALUE=DATE:20260624
+SUMMARY:St John's Day
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestHolidaysTool(t *testing.T) {
+	ctx := context.Background()
+
+	registry := holidays.NewRegistry()
+	calendar, _, _ := registry.Lookup("", "")
+
+	calendars, err := holidays.New(holidays.Config{Link: calendar.Link()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := calendars.Seed(calendar.Link(), strings.NewReader(holidaysCalendar)); err != nil {
+		t.Fatalf("failed to seed calendar: %v", err)
+	}
+
+	tool := NewHolidaysTool(calendars, registry)
+	tool.now = func() time.Time { return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC) }
+
+	const region = "Catalonia, Spain"
+	easterMonday := holiday{Date: "2026-04-06", Weekday: "Monday", Name: "Easter Monday", Region: region, DaysOff: 3, LongWeekend: true}
+	stJohn := holiday{Date: "2026-06-24", Weekday: "Wednesday", Name: "St John's Day", Region: region, DaysOff: 1}
+	assumption := holiday{Date: "2026-08-15", Weekday: "Saturday", Name: "Assumption Day", Region: region, DaysOff: 2}
+	nationalDay := holiday{Date: "2026-10-01", Weekday: "Thursday", Name: "National Day", Region: region, DaysOff: 1, BridgeDay: "2026-10-02"}
+	christmas := holiday{Date: "2026-12-25", Weekday: "Friday", Name: "Christmas Day", Region: region, DaysOff: 3, LongWeekend: true}
+	newYear := holiday{Date: "2027-01-01", Weekday: "Friday", Name: "New Year's Day", Region: region, DaysOff: 3, LongWeekend: true}
+
+	tests := []struct {
+		name string
+		args string
+		want []holiday
+	}{
+		{
+			name: "the next year from today by default",
+			args: `{}`,
+			want: []holiday{easterMonday, stJohn, assumption, nationalDay, christmas, newYear},
+		},
+		{
+			name: "max count keeps the earliest holidays",
+			args: `{"max_count": 2}`,
+			want: []holiday{easterMonday, stJohn},
+		},
+		{
+			name: "dates are inclusive",
+			args: `{"after_date": "2026-08-15", "before_date": "2026-10-01"}`,
+			want: []holiday{assumption, nationalDay},
+		},
+		{
+			name: "RFC3339 dates",
+			args: `{"after_date": "2026-12-01T00:00:00+01:00", "max_count": 1}`,
+			want: []holiday{christmas},
+		},
+		{
+			name: "explicit after date before today",
+			args: `{"after_date": "2026-01-01", "before_date": "2026-01-31"}`,
+			want: []holiday{{Date: "2026-01-01", Weekday: "Thursday", Name: "New Year's Day", Region: region, DaysOff: 1, BridgeDay: "2026-01-02"}},
+		},
+		{
+			name: "no holidays in the window",
+			args: `{"after_date": "2026-02-01", "before_date": "2026-02-28"}`,
+			want: []holiday{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tool.Execute(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []holiday
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("expected JSON holidays, got %q", out)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("holidays mismatch (-got +want):\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	t.Run("unknown region falls back to the calendar of its country", func(t *testing.T) {
+		germany, _, _ := registry.Lookup("DE", "")
+		if err := calendars.Seed(germany.Link(), strings.NewReader(holidaysCalendar)); err != nil {
+			t.Fatalf("failed to seed calendar: %v", err)
+		}
+
+		out, err := tool.Execute(ctx, `{"country": "de", "region": "hh", "max_count": 1}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		note, list, ok := strings.Cut(out, ": ")
+		if !ok || !strings.Contains(note, "no holiday calendar for region DE-HH, its regional holidays are not covered") {
+			t.Fatalf("expected a note on the regional holidays, got %q", out)
+		}
+
+		var got []holiday
+		if err := json.Unmarshal([]byte(list), &got); err != nil || len(got) != 1 || got[0].Region != "Germany" {
+			t.Errorf("expected the holidays of Germany, got %q", list)
+		}
+	})
+
+	failures := []struct {
+		name string
+		args string
+		want string
+	}{
+		{name: "invalid after date", args: `{"after_date": "tomorrow"}`, want: "invalid after_date"},
+		{name: "invalid before date", args: `{"before_date": "01/01/2027"}`, want: "invalid before_date"},
+		{name: "reversed window", args: `{"after_date": "2026-05-01", "before_date": "2026-04-01"}`, want: "before_date must not be before after_date"},
+		{name: "unknown country", args: `{"country": "XX"}`, want: "no holiday calendar for country XX"},
+		{name: "region code of another country", args: `{"country": "PT", "region": "DE-BY"}`, want: "region DE-BY is not in country PT"},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tool.Execute(ctx, tt.args)
+			if err != nil || !strings.HasPrefix(out, tt.want) {
+				t.Errorf("expected %q, got %q, err %v", tt.want, out, err)
+			}
+		})
+	}
+}
@@ -2,24 +2,139 @@ package tools
 
 import (
 	"context"
-	"github.com/openai/openai-go/v2"
+	"encoding/json"
+	"github.com/acai-travel/tech-challenge/internal/weather"
+	"log/slog"
+	"strings"
 	"time"
+
+	// The time zone database is embedded, so that time zones resolve on hosts without one.
+	_ "time/tzdata"
 )
 
-type TodayTool struct{}
+// TodayTool tells the current date and time, in the server's time zone or at a given location. The zero value uses
+// the system clock and cannot resolve locations.
+type TodayTool struct {
+	client *weather.Client
+	now    func() time.Time
+}
+
+// NewTodayTool returns a tool resolving the time zones of locations with the weather client, reading the time from
+// now, or from the system clock when it is nil.
+func NewTodayTool(client *weather.Client, now func() time.Time) *TodayTool {
+	return &TodayTool{client: client, now: now}
+}
 
 func (t *TodayTool) Name() string {
 	return "get_today_date"
 }
 
 func (t *TodayTool) Description() string {
-	return "Get the current date and time in RFC3339 format."
+	return "Get the current local date, time, weekday, UTC offset and whether daylight saving time is active, as JSON. " +
+		"Pass the location or time zone of the user, e.g. when they are travelling, to get their local date."
+}
+
+func (t *TodayTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]string{
+				"type":        "string",
+				"description": "Optional city name, postcode, IATA airport code or 'lat,lon' coordinates whose local time to get.",
+			},
+			"timezone": map[string]string{
+				"type":        "string",
+				"description": "Optional IANA time zone, e.g. 'Pacific/Auckland', taking precedence over location. If neither is provided, the server's time zone is used.",
+			},
+		},
+	}
 }
 
-func (t *TodayTool) Parameters() openai.FunctionParameters {
-	return openai.FunctionParameters{}
+// today is the current local time as returned to the model.
+type today struct {
+	Date         string `json:"date"`
+	Time         string `json:"time"`
+	Weekday      string `json:"weekday"`
+	Timezone     string `json:"timezone"`
+	Abbreviation string `json:"abbreviation"`
+	UTCOffset    string `json:"utc_offset"`
+	DST          bool   `json:"dst"`
+	Location     string `json:"location,omitempty"`
+	RFC3339      string `json:"rfc3339"`
 }
 
 func (t *TodayTool) Execute(ctx context.Context, args ...string) (string, error) {
-	return time.Now().Format(time.RFC3339), nil
+	var parameters struct {
+		Location string `json:"location"`
+		Timezone string `json:"timezone"`
+	}
+
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		if err := json.Unmarshal([]byte(args[0]), &parameters); err != nil {
+			return "failed to parse tool call arguments: " + err.Error(), nil
+		}
+	}
+
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+
+	loc := now().Location()
+	var location string
+
+	switch {
+	case strings.TrimSpace(parameters.Timezone) != "":
+		l, err := time.LoadLocation(strings.TrimSpace(parameters.Timezone))
+		if err != nil {
+			return "unknown time zone " + parameters.Timezone + ", expected an IANA time zone like 'Europe/Madrid'", nil
+		}
+		loc = l
+
+	case strings.TrimSpace(parameters.Location) != "":
+		if t.client == nil {
+			return "locations cannot be resolved, pass a time zone instead", nil
+		}
+
+		slog.InfoContext(ctx, "Resolving time zone", "location", parameters.Location)
+
+		resp, err := t.client.GetCurrentWeather(ctx, parameters.Location, false)
+		if err != nil {
+			return "Weather service error: " + err.Error(), nil
+		}
+
+		l, err := time.LoadLocation(resp.Location.TzID)
+		if err != nil {
+			return "unknown time zone " + resp.Location.TzID + " for " + parameters.Location, nil
+		}
+		loc = l
+		location = resp.Location.Name + ", " + resp.Location.Country
+	}
+
+	local := now().In(loc)
+	abbreviation, _ := local.Zone()
+
+	// The server's zone is named "Local", which tells the model nothing.
+	zone := loc.String()
+	if loc == time.Local {
+		zone = abbreviation
+	}
+
+	out, err := json.Marshal(today{
+		Date:         local.Format(time.DateOnly),
+		Time:         local.Format(time.TimeOnly),
+		Weekday:      local.Weekday().String(),
+		Timezone:     zone,
+		Abbreviation: abbreviation,
+		UTCOffset:    local.Format("-07:00"),
+		DST:          local.IsDST(),
+		Location:     location,
+		RFC3339:      local.Format(time.RFC3339),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
 }
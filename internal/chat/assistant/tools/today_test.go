@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTodayTool(t *testing.T) {
+	ctx := context.Background()
+
+	const newYork = `{"location": {"name": "New York", "country": "United States of America", "tz_id": "America/New_York"}}`
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		args   string
+		client string
+		want   today
+	}{
+		{
+			name: "time zone ahead of UTC",
+			now:  time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC),
+			args: `{"timezone": "Pacific/Auckland"}`,
+			want: today{Date: "2025-06-02", Time: "08:00:00", Weekday: "Monday", Timezone: "Pacific/Auckland", Abbreviation: "NZST", UTCOffset: "+12:00", RFC3339: "2025-06-02T08:00:00+12:00"},
+		},
+		{
+			name: "daylight saving time",
+			now:  time.Date(2025, 7, 4, 16, 0, 0, 0, time.UTC),
+			args: `{"timezone": " America/New_York "}`,
+			want: today{Date: "2025-07-04", Time: "12:00:00", Weekday: "Friday", Timezone: "America/New_York", Abbreviation: "EDT", UTCOffset: "-04:00", DST: true, RFC3339: "2025-07-04T12:00:00-04:00"},
+		},
+		{
+			name:   "location resolved to its time zone",
+			now:    time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC),
+			args:   `{"location": "New York"}`,
+			client: newYork,
+			want:   today{Date: "2025-01-15", Time: "12:00:00", Weekday: "Wednesday", Timezone: "America/New_York", Abbreviation: "EST", UTCOffset: "-05:00", Location: "New York, United States of America", RFC3339: "2025-01-15T12:00:00-05:00"},
+		},
+		{
+			name:   "time zone takes precedence over location",
+			now:    time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC),
+			args:   `{"location": "New York", "timezone": "Europe/Madrid"}`,
+			client: newYork,
+			want:   today{Date: "2025-01-15", Time: "18:00:00", Weekday: "Wednesday", Timezone: "Europe/Madrid", Abbreviation: "CET", UTCOffset: "+01:00", RFC3339: "2025-01-15T18:00:00+01:00"},
+		},
+		{
+			name: "time zone of the clock by default",
+			now:  time.Date(2025, 7, 4, 16, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			args: `{}`,
+			want: today{Date: "2025-07-04", Time: "16:00:00", Weekday: "Friday", Timezone: "CEST", Abbreviation: "CEST", UTCOffset: "+02:00", RFC3339: "2025-07-04T16:00:00+02:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewTodayTool(nil, func() time.Time { return tt.now })
+			if tt.client != "" {
+				tool.client = weatherClient(t, tt.client, func(path string, query url.Values) {
+					if query.Get("q") != "New York" {
+						t.Errorf("unexpected location %q", query.Get("q"))
+					}
+				})
+			}
+
+			out, err := tool.Execute(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got today
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("expected JSON date, got %q", out)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("today mismatch (-got +want):\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	t.Run("server time zone is named after its abbreviation", func(t *testing.T) {
+		out, err := NewTodayTool(nil, nil).Execute(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got today
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("expected JSON date, got %q", out)
+		}
+
+		abbreviation, _ := time.Now().Zone()
+		if got.Timezone != abbreviation || got.Abbreviation != abbreviation {
+			t.Errorf("expected time zone %q, got %+v", abbreviation, got)
+		}
+	})
+
+	failures := []struct {
+		name   string
+		args   string
+		client string
+		want   string
+	}{
+		{name: "invalid time zone", args: `{"timezone": "Mars/Olympus_Mons"}`, want: "unknown time zone Mars/Olympus_Mons"},
+		{name: "location without weather client", args: `{"location": "New York"}`, want: "locations cannot be resolved"},
+		{name: "location with an unknown time zone", args: `{"location": "Atlantis"}`, client: `{"location": {"name": "Atlantis", "tz_id": "Ocean/Atlantis"}}`, want: "unknown time zone Ocean/Atlantis for Atlantis"},
+		{name: "invalid arguments", args: `{"timezone": 1}`, want: "failed to parse tool call arguments"},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewTodayTool(nil, nil)
+			if tt.client != "" {
+				tool.client = weatherClient(t, tt.client, nil)
+			}
+
+			out, err := tool.Execute(ctx, tt.args)
+			if err != nil || !strings.HasPrefix(out, tt.want) {
+				t.Errorf("expected %q, got %q, err %v", tt.want, out, err)
+			}
+		})
+	}
+}
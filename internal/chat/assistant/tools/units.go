@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// units selects the measurement system used to present weather data to the model.
+type units string
+
+const (
+	metric   units = "metric"
+	imperial units = "imperial"
+)
+
+// unitsParameter is the JSON schema of the optional "units" tool parameter.
+var unitsParameter = map[string]any{
+	"type":        "string",
+	"enum":        []string{string(metric), string(imperial)},
+	"description": "Optional measurement system, metric (default) or imperial.",
+}
+
+func parseUnits(v string) (units, error) {
+	switch u := units(strings.ToLower(strings.TrimSpace(v))); u {
+	case "":
+		return metric, nil
+	case metric, imperial:
+		return u, nil
+	default:
+		return "", fmt.Errorf("unknown units %q, expected metric or imperial", v)
+	}
+}
+
+// temperature formats whichever of the given values matches the units.
+func (u units) temperature(c, f float64) string {
+	if u == imperial {
+		return fmt.Sprintf("%.1f °F", f)
+	}
+	return fmt.Sprintf("%.1f °C", c)
+}
+
+func (u units) speed(kph, mph float64) string {
+	if u == imperial {
+		return fmt.Sprintf("%.1f mph", mph)
+	}
+	return fmt.Sprintf("%.1f km/h", kph)
+}
+
+func (u units) distance(km, miles float64) string {
+	if u == imperial {
+		return fmt.Sprintf("%.1f mi", miles)
+	}
+	return fmt.Sprintf("%.1f km", km)
+}
+
+func (u units) precipitation(mm, in float64) string {
+	if u == imperial {
+		return fmt.Sprintf("%.2f in", in)
+	}
+	return fmt.Sprintf("%.1f mm", mm)
+}
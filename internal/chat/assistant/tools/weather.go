@@ -3,49 +3,102 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/acai-travel/tech-challenge/internal/weather"
-	"github.com/openai/openai-go/v2"
 	"log/slog"
 )
 
-type WeatherTool struct{}
+type WeatherTool struct {
+	client *weather.Client
+}
+
+func NewWeatherTool(client *weather.Client) *WeatherTool {
+	return &WeatherTool{client: client}
+}
 
 func (w *WeatherTool) Name() string {
 	return "get_weather"
 }
 
 func (w *WeatherTool) Description() string {
-	return "Get weather at the given location"
+	return "Get current weather conditions at the given location, including temperature, feels-like temperature, wind, gusts, humidity, precipitation, UV index and visibility, as JSON."
 }
 
-func (w *WeatherTool) Parameters() openai.FunctionParameters {
-	return openai.FunctionParameters{
+func (w *WeatherTool) Parameters() map[string]any {
+	return map[string]any{
 		"type": "object",
 		"properties": map[string]any{
 			"location": map[string]string{
-				"type": "string",
+				"type":        "string",
+				"description": "City name, postcode, IATA airport code or 'lat,lon' coordinates",
 			},
+			"units": unitsParameter,
 		},
 		"required": []string{"location"},
 	}
 }
 
+// currentConditions is the compact summary of the current weather returned to the model.
+type currentConditions struct {
+	Location      string  `json:"location"`
+	LocalTime     string  `json:"local_time"`
+	LastUpdated   string  `json:"last_updated"`
+	Condition     string  `json:"condition"`
+	Temperature   string  `json:"temperature"`
+	FeelsLike     string  `json:"feels_like"`
+	Wind          string  `json:"wind"`
+	Gusts         string  `json:"gusts"`
+	Humidity      string  `json:"humidity"`
+	Cloud         string  `json:"cloud_cover"`
+	Precipitation string  `json:"precipitation"`
+	UV            float64 `json:"uv_index"`
+	Visibility    string  `json:"visibility"`
+}
+
 func (w *WeatherTool) Execute(ctx context.Context, args ...string) (string, error) {
 
 	var parameters struct {
 		Location string `json:"location"`
+		Units    string `json:"units"`
 	}
 
 	if err := json.Unmarshal([]byte(args[0]), &parameters); err != nil {
 		return "failed to parse tool call arguments: " + err.Error(), nil
 	}
 
-	slog.InfoContext(ctx, "Executing WeatherTool", "location", parameters.Location)
+	u, err := parseUnits(parameters.Units)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	slog.InfoContext(ctx, "Executing WeatherTool", "location", parameters.Location, "units", u)
+
+	resp, err := w.client.GetCurrentWeather(ctx, parameters.Location, false)
+	if err != nil {
+		// Return error as a user-friendly message
+		return "Weather service error: " + err.Error(), nil
+	}
+
+	current := resp.Current
+	out, err := json.Marshal(currentConditions{
+		Location:      resp.Location.Name + ", " + resp.Location.Country,
+		LocalTime:     resp.Location.Localtime,
+		LastUpdated:   current.LastUpdated,
+		Condition:     current.Condition.Text,
+		Temperature:   u.temperature(current.TempC, current.TempF),
+		FeelsLike:     u.temperature(current.FeelslikeC, current.FeelslikeF),
+		Wind:          u.speed(current.WindKph, current.WindMph) + " " + current.WindDir,
+		Gusts:         u.speed(current.GustKph, current.GustMph),
+		Humidity:      fmt.Sprintf("%d%%", current.Humidity),
+		Cloud:         fmt.Sprintf("%d%%", current.Cloud),
+		Precipitation: u.precipitation(current.PrecipMm, current.PrecipIn),
+		UV:            current.Uv,
+		Visibility:    u.distance(current.VisKm, current.VisMiles),
+	})
 
-	currentWeather, err := weather.GetCurrentWeather(ctx, parameters.Location)
 	if err != nil {
 		return "", err
 	}
 
-	return currentWeather.Condition.Text, nil
+	return string(out), nil
 }
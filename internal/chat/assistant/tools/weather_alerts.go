@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/acai-travel/tech-challenge/internal/weather"
+	"log/slog"
+)
+
+type WeatherAlertsTool struct {
+	client *weather.Client
+}
+
+func NewWeatherAlertsTool(client *weather.Client) *WeatherAlertsTool {
+	return &WeatherAlertsTool{client: client}
+}
+
+func (w *WeatherAlertsTool) Name() string {
+	return "get_weather_alerts"
+}
+
+func (w *WeatherAlertsTool) Description() string {
+	return "Get active government weather alerts (storms, floods, heat, etc.) issued for the given location as JSON, with headline, severity, affected areas and validity period. Use it to warn travellers about severe weather at their destination."
+}
+
+func (w *WeatherAlertsTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]string{
+				"type":        "string",
+				"description": "Given location",
+			},
+		},
+		"required": []string{"location"},
+	}
+}
+
+// weatherAlert is the summary of an alert returned to the model.
+type weatherAlert struct {
+	Headline    string `json:"headline"`
+	Event       string `json:"event,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Urgency     string `json:"urgency,omitempty"`
+	Areas       string `json:"areas,omitempty"`
+	Effective   string `json:"effective,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Description string `json:"description,omitempty"`
+	Instruction string `json:"instruction,omitempty"`
+}
+
+func (w *WeatherAlertsTool) Execute(ctx context.Context, args ...string) (string, error) {
+
+	var parameters struct {
+		Location string `json:"location"`
+	}
+
+	if err := json.Unmarshal([]byte(args[0]), &parameters); err != nil {
+		return "failed to parse tool call arguments: " + err.Error(), nil
+	}
+
+	slog.InfoContext(ctx, "Executing WeatherAlertsTool", "location", parameters.Location)
+
+	forecast, err := w.client.GetWeatherForecast(ctx, parameters.Location, 1, true, false)
+	if err != nil {
+		// Return error as a user-friendly message
+		return "Weather alerts service error: " + err.Error(), nil
+	}
+
+	location := forecast.Location.Name + ", " + forecast.Location.Country
+	if len(forecast.Alerts.Alert) == 0 {
+		return "No active weather alerts for " + location + ".", nil
+	}
+
+	summary := struct {
+		Location string         `json:"location"`
+		Alerts   []weatherAlert `json:"alerts"`
+	}{Location: location}
+
+	for _, a := range forecast.Alerts.Alert {
+		summary.Alerts = append(summary.Alerts, weatherAlert{
+			Headline:    a.Headline,
+			Event:       a.Event,
+			Severity:    a.Severity,
+			Urgency:     a.Urgency,
+			Areas:       a.Areas,
+			Effective:   a.Effective,
+			Expires:     a.Expires,
+			Description: a.Desc,
+			Instruction: a.Instruction,
+		})
+	}
+
+	out, err := json.Marshal(summary)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWeatherAlertsTool(t *testing.T) {
+	ctx := context.Background()
+
+	const location = `"location": {"name": "Valencia", "country": "Spain"}`
+
+	t.Run("active alerts", func(t *testing.T) {
+		client := weatherClient(t, `{`+location+`, "alerts": {"alert": [{
+			"headline": "Aviso rojo. Lluvias en litoral sur de Valencia", "msgtype": "Alert", "severity": "Extreme",
+			"urgency": "Immediate", "areas": "Litoral sur de Valencia", "category": "Met", "certainty": "Likely",
+			"event": "Aviso rojo de lluvias", "note": "", "effective": "2025-10-22T09:00:00+02:00",
+			"expires": "2025-10-22T23:59:59+02:00", "desc": "Precipitación acumulada en una hora: 90 mm.",
+			"instruction": "Evite desplazamientos."
+		}]}}`, func(path string, query url.Values) {
+			if query.Get("alerts") != "yes" || query.Get("days") != "1" {
+				t.Errorf("expected a day of forecast with alerts, got %s", query.Encode())
+			}
+		})
+
+		out, err := NewWeatherAlertsTool(client).Execute(ctx, `{"location": "Valencia"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got struct {
+			Location string         `json:"location"`
+			Alerts   []weatherAlert `json:"alerts"`
+		}
+
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("expected JSON alerts, got %q", out)
+		}
+
+		want := []weatherAlert{{
+			Headline:    "Aviso rojo. Lluvias en litoral sur de Valencia",
+			Event:       "Aviso rojo de lluvias",
+			Severity:    "Extreme",
+			Urgency:     "Immediate",
+			Areas:       "Litoral sur de Valencia",
+			Effective:   "2025-10-22T09:00:00+02:00",
+			Expires:     "2025-10-22T23:59:59+02:00",
+			Description: "Precipitación acumulada en una hora: 90 mm.",
+			Instruction: "Evite desplazamientos.",
+		}}
+
+		if got.Location != "Valencia, Spain" || !cmp.Equal(got.Alerts, want) {
+			t.Errorf("alerts mismatch for %s (-got +want):\n%s", got.Location, cmp.Diff(got.Alerts, want))
+		}
+	})
+
+	t.Run("no alerts is reported to the model", func(t *testing.T) {
+		client := weatherClient(t, `{`+location+`, "alerts": {"alert": []}}`, nil)
+
+		out, err := NewWeatherAlertsTool(client).Execute(ctx, `{"location": "Valencia"}`)
+		if err != nil || out != "No active weather alerts for Valencia, Spain." {
+			t.Errorf("expected no alerts message, got %q, err %v", out, err)
+		}
+	})
+}
@@ -3,23 +3,37 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/acai-travel/tech-challenge/internal/weather"
-	"github.com/openai/openai-go/v2"
 	"log/slog"
+	"strings"
+	"time"
 )
 
-type WeatherForecastTool struct{}
+const (
+	defaultForecastDays = 3
+	maxForecastDays     = 14
+)
+
+type WeatherForecastTool struct {
+	client *weather.Client
+	now    func() time.Time
+}
+
+func NewWeatherForecastTool(client *weather.Client) *WeatherForecastTool {
+	return &WeatherForecastTool{client: client, now: time.Now}
+}
 
 func (w *WeatherForecastTool) Name() string {
 	return "get_weather_forecast"
 }
 
 func (w *WeatherForecastTool) Description() string {
-	return "Get weather forecast at the given location for the given days"
+	return "Get the weather forecast at the given location as JSON. Use daily granularity for temperatures, precipitation, chance of rain or snow, wind, UV and sunrise/sunset per day, and hourly granularity with a time window to answer questions about a specific time, like 'will it rain at 6pm tomorrow'. Times are in the location's local time."
 }
 
-func (w *WeatherForecastTool) Parameters() openai.FunctionParameters {
-	return openai.FunctionParameters{
+func (w *WeatherForecastTool) Parameters() map[string]any {
+	return map[string]any{
 		"type": "object",
 		"properties": map[string]any{
 			"location": map[string]string{
@@ -28,38 +42,249 @@ func (w *WeatherForecastTool) Parameters() openai.FunctionParameters {
 			},
 			"days": map[string]string{
 				"type":        "integer",
-				"description": "Number of days to forecast (1-7)",
+				"description": "Optional number of days to forecast (1-14). Defaults to 3, or to the days needed to cover the time window.",
+			},
+			"granularity": map[string]any{
+				"type":        "string",
+				"enum":        []string{"daily", "hourly"},
+				"description": "Optional granularity of the forecast, daily (default) or hourly.",
+			},
+			"from": map[string]string{
+				"type":        "string",
+				"description": "Optional start of the time window in local time, as 'YYYY-MM-DD' or 'YYYY-MM-DD HH:MM'.",
 			},
+			"to": map[string]string{
+				"type":        "string",
+				"description": "Optional end of the time window in local time, as 'YYYY-MM-DD' or 'YYYY-MM-DD HH:MM'. Dates include the whole day.",
+			},
+			"units": unitsParameter,
 		},
 		"required": []string{"location"},
 	}
 }
 
+// dailyForecast is the summary of a forecast day returned to the model.
+type dailyForecast struct {
+	Date          string  `json:"date"`
+	Condition     string  `json:"condition"`
+	MaxTemp       string  `json:"max_temperature"`
+	MinTemp       string  `json:"min_temperature"`
+	AvgTemp       string  `json:"avg_temperature"`
+	Precipitation string  `json:"total_precipitation"`
+	ChanceOfRain  int     `json:"chance_of_rain"`
+	ChanceOfSnow  int     `json:"chance_of_snow"`
+	MaxWind       string  `json:"max_wind"`
+	Humidity      float64 `json:"avg_humidity"`
+	UV            float64 `json:"uv_index"`
+	Sunrise       string  `json:"sunrise"`
+	Sunset        string  `json:"sunset"`
+	MoonPhase     string  `json:"moon_phase"`
+}
+
+// hourlyForecast is the summary of a forecast hour returned to the model.
+type hourlyForecast struct {
+	Time          string  `json:"time"`
+	Condition     string  `json:"condition"`
+	Temperature   string  `json:"temperature"`
+	FeelsLike     string  `json:"feels_like"`
+	ChanceOfRain  int     `json:"chance_of_rain"`
+	ChanceOfSnow  int     `json:"chance_of_snow"`
+	Precipitation string  `json:"precipitation"`
+	Wind          string  `json:"wind"`
+	Gusts         string  `json:"gusts"`
+	Humidity      int     `json:"humidity"`
+	Cloud         int     `json:"cloud_cover"`
+	UV            float64 `json:"uv_index"`
+}
+
+type forecastSummary struct {
+	Location  string           `json:"location"`
+	LocalTime string           `json:"local_time"`
+	Days      []dailyForecast  `json:"days,omitempty"`
+	Hours     []hourlyForecast `json:"hours,omitempty"`
+}
+
 func (w *WeatherForecastTool) Execute(ctx context.Context, args ...string) (string, error) {
 
 	var parameters struct {
-		Location string `json:"location"`
-		Days     int    `json:"days"`
+		Location    string `json:"location"`
+		Days        int    `json:"days"`
+		Granularity string `json:"granularity"`
+		From        string `json:"from"`
+		To          string `json:"to"`
+		Units       string `json:"units"`
 	}
 
 	if err := json.Unmarshal([]byte(args[0]), &parameters); err != nil {
 		return "failed to parse tool call arguments: " + err.Error(), nil
 	}
 
-	slog.InfoContext(ctx, "Executing WeatherForecastTool", "location", parameters.Location, "days", parameters.Days)
+	u, err := parseUnits(parameters.Units)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	from, err := parseWindow(parameters.From, false)
+	if err != nil {
+		return "invalid 'from': " + err.Error(), nil
+	}
+
+	to, err := parseWindow(parameters.To, true)
+	if err != nil {
+		return "invalid 'to': " + err.Error(), nil
+	}
 
-	forecast, err := weather.GetWeatherForecast(ctx, parameters.Location, parameters.Days, false, false)
+	// Unless given, the days cover the window, which ends on the later of its bounds.
+	last := to
+	if from.After(last) {
+		last = from
+	}
+	sized := parameters.Days <= 0 && !last.IsZero()
+
+	days := parameters.Days
+	if days <= 0 {
+		days = defaultForecastDays
+	}
+	days = max(1, min(days, maxForecastDays))
+
+	// The location's local date is unknown until the API answers, the days are first counted from the UTC date.
+	if sized {
+		days = forecastDays(w.now().UTC(), last)
+	}
+
+	slog.InfoContext(ctx, "Executing WeatherForecastTool", "location", parameters.Location, "days", days, "granularity", parameters.Granularity, "from", parameters.From, "to", parameters.To)
+
+	forecast, err := w.client.GetWeatherForecast(ctx, parameters.Location, days, false, false)
 	if err != nil {
 		// Return error as a user-friendly message
 		return "Weather forecast service error: " + err.Error(), nil
 	}
 
-	response := "Weather forecast for " + parameters.Location + ":\n"
+	// Locations behind UTC may still be on the previous day, whose forecast then falls a day short of the window.
+	if local, err := time.Parse(weather.TimeLayout, forecast.Location.Localtime); err == nil && sized {
+		if needed := forecastDays(local, last); needed > days {
+			forecast, err = w.client.GetWeatherForecast(ctx, parameters.Location, needed, false, false)
+			if err != nil {
+				return "Weather forecast service error: " + err.Error(), nil
+			}
+		}
+	}
+
+	summary := forecastSummary{
+		Location:  forecast.Location.Name + ", " + forecast.Location.Country,
+		LocalTime: forecast.Location.Localtime,
+	}
+
+	hourly := strings.EqualFold(parameters.Granularity, "hourly")
+
+	for _, day := range forecast.Forecast.Forecastday {
+		date, err := time.Parse(time.DateOnly, day.Date)
+		if err != nil {
+			continue
+		}
+
+		if !hourly {
+			if inWindow(date, date.Add(24*time.Hour-time.Nanosecond), from, to) {
+				summary.Days = append(summary.Days, dailyForecast{
+					Date:          day.Date,
+					Condition:     day.Day.Condition.Text,
+					MaxTemp:       u.temperature(day.Day.MaxtempC, day.Day.MaxtempF),
+					MinTemp:       u.temperature(day.Day.MintempC, day.Day.MintempF),
+					AvgTemp:       u.temperature(day.Day.AvgtempC, day.Day.AvgtempF),
+					Precipitation: u.precipitation(day.Day.TotalprecipMm, day.Day.TotalprecipIn),
+					ChanceOfRain:  day.Day.DailyChanceOfRain,
+					ChanceOfSnow:  day.Day.DailyChanceOfSnow,
+					MaxWind:       u.speed(day.Day.MaxwindKph, day.Day.MaxwindMph),
+					Humidity:      day.Day.Avghumidity,
+					UV:            day.Day.Uv,
+					Sunrise:       day.Astro.Sunrise,
+					Sunset:        day.Astro.Sunset,
+					MoonPhase:     day.Astro.MoonPhase,
+				})
+			}
+
+			continue
+		}
+
+		for _, hour := range day.Hour {
+			t, err := time.Parse(weather.TimeLayout, hour.Time)
+			if err != nil || !inWindow(t, t, from, to) {
+				continue
+			}
+
+			summary.Hours = append(summary.Hours, hourlyForecast{
+				Time:          hour.Time,
+				Condition:     hour.Condition.Text,
+				Temperature:   u.temperature(hour.TempC, hour.TempF),
+				FeelsLike:     u.temperature(hour.FeelslikeC, hour.FeelslikeF),
+				ChanceOfRain:  hour.ChanceOfRain,
+				ChanceOfSnow:  hour.ChanceOfSnow,
+				Precipitation: u.precipitation(hour.PrecipMm, hour.PrecipIn),
+				Wind:          u.speed(hour.WindKph, hour.WindMph) + " " + hour.WindDir,
+				Gusts:         u.speed(hour.GustKph, hour.GustMph),
+				Humidity:      hour.Humidity,
+				Cloud:         hour.Cloud,
+				UV:            hour.Uv,
+			})
+		}
+	}
+
+	if len(summary.Days) == 0 && len(summary.Hours) == 0 {
+		return fmt.Sprintf("No forecast available for %s in the requested time window, the forecast covers %d days from %s.", summary.Location, len(forecast.Forecast.Forecastday), summary.LocalTime), nil
+	}
+
+	out, err := json.Marshal(summary)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// forecastDays returns the number of forecast days from the date of today up to the date of last, both inclusive,
+// within the range of the API.
+func forecastDays(today, last time.Time) int {
+	start := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC)
+
+	return max(1, min(int(end.Sub(start).Hours()/24)+1, maxForecastDays))
+}
+
+// parseWindow parses a time window bound in local time. A bare date stands for the start of the day, or for its end
+// when end is set, so that a date range includes its last day.
+func parseWindow(v string, end bool) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{weather.TimeLayout, "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected 'YYYY-MM-DD' or 'YYYY-MM-DD HH:MM', got %q", v)
+	}
+
+	if end {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return t, nil
+}
+
+// inWindow reports whether the period [start, end] overlaps the window, where zero bounds are open.
+func inWindow(start, end, from, to time.Time) bool {
+	if !from.IsZero() && end.Before(from) {
+		return false
+	}
 
-	for _, day := range forecast.Forecastday {
-		slog.InfoContext(ctx, "Forecast", "date", day.Date, "condition", day.Day.Condition.Text)
-		response += day.Date + ": " + day.Day.Condition.Text + "\n"
+	if !to.IsZero() && start.After(to) {
+		return false
 	}
 
-	return response, nil
+	return true
 }
@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acai-travel/tech-challenge/internal/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+// weatherClient returns a client of a WeatherAPI answering every request with body, after passing its query to
+// check when set.
+func weatherClient(t *testing.T, body string, check func(path string, query url.Values)) *weather.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r.URL.Path, r.URL.Query())
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return weather.NewClient(weather.Config{BaseURL: srv.URL, APIKey: "secret", HTTPClient: srv.Client()})
+}
+
+const forecastResponse = `{
+	"location": {"name": "Barcelona", "country": "Spain", "tz_id": "Europe/Madrid", "localtime": "2025-06-01 10:00"},
+	"forecast": {"forecastday": [
+		{
+			"date": "2025-06-01",
+			"day": {"maxtemp_c": 25, "maxtemp_f": 77, "mintemp_c": 18, "mintemp_f": 64.4, "avgtemp_c": 21.5, "avgtemp_f": 70.7, "maxwind_kph": 20, "maxwind_mph": 12.4, "totalprecip_mm": 0, "totalprecip_in": 0, "daily_chance_of_rain": 10, "condition": {"text": "Sunny"}},
+			"astro": {"sunrise": "06:18 AM", "sunset": "09:19 PM"},
+			"hour": [
+				{"time": "2025-06-01 17:00", "temp_c": 24, "temp_f": 75.2, "chance_of_rain": 0, "condition": {"text": "Sunny"}},
+				{"time": "2025-06-01 18:00", "temp_c": 23, "temp_f": 73.4, "chance_of_rain": 5, "condition": {"text": "Sunny"}}
+			]
+		},
+		{
+			"date": "2025-06-02",
+			"day": {"maxtemp_c": 22, "maxtemp_f": 71.6, "mintemp_c": 17, "mintemp_f": 62.6, "avgtemp_c": 19.5, "avgtemp_f": 67.1, "maxwind_kph": 30, "maxwind_mph": 18.6, "totalprecip_mm": 12.5, "totalprecip_in": 0.49, "daily_chance_of_rain": 85, "condition": {"text": "Moderate rain"}},
+			"astro": {"sunrise": "06:18 AM", "sunset": "09:20 PM"},
+			"hour": [
+				{"time": "2025-06-02 17:00", "temp_c": 19, "temp_f": 66.2, "chance_of_rain": 80, "precip_mm": 2.1, "precip_in": 0.08, "wind_kph": 25, "wind_mph": 15.5, "wind_dir": "SW", "condition": {"text": "Moderate rain"}},
+				{"time": "2025-06-02 18:00", "temp_c": 18, "temp_f": 64.4, "chance_of_rain": 90, "precip_mm": 3.4, "precip_in": 0.13, "wind_kph": 28, "wind_mph": 17.4, "wind_dir": "SW", "condition": {"text": "Heavy rain"}}
+			]
+		}
+	]}
+}`
+
+func TestWeatherForecastTool(t *testing.T) {
+	ctx := context.Background()
+	now := func() time.Time { return time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		now  time.Time
+		args string
+		days []string
+		want forecastSummary
+	}{
+		{
+			name: "daily forecast in metric units",
+			args: `{"location": "Barcelona"}`,
+			days: []string{"3"},
+			want: forecastSummary{Location: "Barcelona, Spain", LocalTime: "2025-06-01 10:00", Days: []dailyForecast{
+				{Date: "2025-06-01", Condition: "Sunny", MaxTemp: "25.0 °C", MinTemp: "18.0 °C", AvgTemp: "21.5 °C", Precipitation: "0.0 mm", ChanceOfRain: 10, MaxWind: "20.0 km/h", Sunrise: "06:18 AM", Sunset: "09:19 PM"},
+				{Date: "2025-06-02", Condition: "Moderate rain", MaxTemp: "22.0 °C", MinTemp: "17.0 °C", AvgTemp: "19.5 °C", Precipitation: "12.5 mm", ChanceOfRain: 85, MaxWind: "30.0 km/h", Sunrise: "06:18 AM", Sunset: "09:20 PM"},
+			}},
+		},
+		{
+			name: "daily forecast in imperial units within the window",
+			args: `{"location": "Barcelona", "from": "2025-06-02", "to": "2025-06-02", "units": "imperial"}`,
+			days: []string{"2"},
+			want: forecastSummary{Location: "Barcelona, Spain", LocalTime: "2025-06-01 10:00", Days: []dailyForecast{
+				{Date: "2025-06-02", Condition: "Moderate rain", MaxTemp: "71.6 °F", MinTemp: "62.6 °F", AvgTemp: "67.1 °F", Precipitation: "0.49 in", ChanceOfRain: 85, MaxWind: "18.6 mph", Sunrise: "06:18 AM", Sunset: "09:20 PM"},
+			}},
+		},
+		{
+			name: "hourly forecast at a given time",
+			args: `{"location": "Barcelona", "granularity": "hourly", "from": "2025-06-02 18:00", "to": "2025-06-02 18:00"}`,
+			days: []string{"2"},
+			want: forecastSummary{Location: "Barcelona, Spain", LocalTime: "2025-06-01 10:00", Hours: []hourlyForecast{
+				{Time: "2025-06-02 18:00", Condition: "Heavy rain", Temperature: "18.0 °C", FeelsLike: "0.0 °C", ChanceOfRain: 90, Precipitation: "3.4 mm", Wind: "28.0 km/h SW", Gusts: "0.0 km/h"},
+			}},
+		},
+		{
+			name: "days cover a window starting past the default",
+			args: `{"location": "Barcelona", "from": "2025-06-09"}`,
+			days: []string{"9"},
+		},
+		{
+			name: "days cover the end of the window",
+			args: `{"location": "Barcelona", "granularity": "hourly", "from": "2025-06-01 18:00", "to": "2025-06-06"}`,
+			days: []string{"6"},
+			want: forecastSummary{Location: "Barcelona, Spain", LocalTime: "2025-06-01 10:00", Hours: []hourlyForecast{
+				{Time: "2025-06-01 18:00", Condition: "Sunny", Temperature: "23.0 °C", FeelsLike: "0.0 °C", ChanceOfRain: 5, Precipitation: "0.0 mm", Wind: "0.0 km/h ", Gusts: "0.0 km/h"},
+				{Time: "2025-06-02 17:00", Condition: "Moderate rain", Temperature: "19.0 °C", FeelsLike: "0.0 °C", ChanceOfRain: 80, Precipitation: "2.1 mm", Wind: "25.0 km/h SW", Gusts: "0.0 km/h"},
+				{Time: "2025-06-02 18:00", Condition: "Heavy rain", Temperature: "18.0 °C", FeelsLike: "0.0 °C", ChanceOfRain: 90, Precipitation: "3.4 mm", Wind: "28.0 km/h SW", Gusts: "0.0 km/h"},
+			}},
+		},
+		{
+			name: "days are counted from the local date of the location",
+			// It is already June 2 in UTC, but still June 1 at the location.
+			now:  time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC),
+			args: `{"location": "Barcelona", "to": "2025-06-06"}`,
+			days: []string{"5", "6"},
+		},
+		{
+			name: "explicit days are capped",
+			args: `{"location": "Barcelona", "days": 30}`,
+			days: []string{"14"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var days []string
+			client := weatherClient(t, forecastResponse, func(path string, query url.Values) {
+				if !strings.HasSuffix(path, "/forecast.json") {
+					t.Errorf("unexpected path %q", path)
+				}
+
+				days = append(days, query.Get("days"))
+			})
+
+			tool := NewWeatherForecastTool(client)
+			tool.now = now
+			if !tt.now.IsZero() {
+				tool.now = func() time.Time { return tt.now }
+			}
+
+			out, err := tool.Execute(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(days, tt.days) {
+				t.Errorf("requested days %v, want %v", days, tt.days)
+			}
+
+			if tt.want.Location == "" {
+				return
+			}
+
+			var got forecastSummary
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("expected a JSON forecast, got %q", out)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("forecast mismatch (-got +want):\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	t.Run("invalid window is reported to the model", func(t *testing.T) {
+		tool := NewWeatherForecastTool(weatherClient(t, forecastResponse, func(string, url.Values) {
+			t.Error("unexpected request")
+		}))
+
+		out, err := tool.Execute(ctx, `{"location": "Barcelona", "to": "tomorrow"}`)
+		if err != nil || !strings.HasPrefix(out, "invalid 'to'") {
+			t.Errorf("expected invalid 'to' message, got %q, err %v", out, err)
+		}
+	})
+
+	t.Run("window outside the forecast is reported to the model", func(t *testing.T) {
+		tool := NewWeatherForecastTool(weatherClient(t, forecastResponse, nil))
+		tool.now = now
+
+		out, err := tool.Execute(ctx, `{"location": "Barcelona", "from": "2025-06-05", "to": "2025-06-06"}`)
+		if err != nil || !strings.HasPrefix(out, "No forecast available for Barcelona, Spain") {
+			t.Errorf("expected no forecast message, got %q, err %v", out, err)
+		}
+	})
+}
+
+func TestForecastDays(t *testing.T) {
+	tests := []struct {
+		today, last time.Time
+		want        int
+	}{
+		{today: time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC), last: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), want: 1},
+		{today: time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC), last: time.Date(2025, 6, 2, 0, 30, 0, 0, time.UTC), want: 2},
+		{today: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), last: time.Date(2025, 6, 6, 23, 59, 59, 0, time.UTC), want: 6},
+		{today: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), last: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), want: 1},
+		{today: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), last: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), want: maxForecastDays},
+	}
+
+	for _, tt := range tests {
+		if got := forecastDays(tt.today, tt.last); got != tt.want {
+			t.Errorf("forecastDays(%v, %v) = %d, want %d", tt.today, tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		value   string
+		end     bool
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "2025-06-02", want: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{value: "2025-06-02", end: true, want: time.Date(2025, 6, 2, 23, 59, 59, 999999999, time.UTC)},
+		{value: " 2025-06-02 18:00 ", want: time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)},
+		{value: "2025-06-02 18:00", end: true, want: time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)},
+		{value: "2025-06-02T18:30", want: time.Date(2025, 6, 2, 18, 30, 0, 0, time.UTC)},
+		{value: "2025-06-02T18:30:15", want: time.Date(2025, 6, 2, 18, 30, 15, 0, time.UTC)},
+		{value: "tomorrow", wantErr: true},
+		{value: "02/06/2025", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseWindow(tt.value, tt.end)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseWindow(%q, %t) error = %v, wantErr %t", tt.value, tt.end, err, tt.wantErr)
+			continue
+		}
+
+		if !got.Equal(tt.want) {
+			t.Errorf("parseWindow(%q, %t) = %v, want %v", tt.value, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestInWindow(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 6, 2, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name                 string
+		start, end, from, to time.Time
+		want                 bool
+	}{
+		{name: "open window", start: at(10), end: at(11), want: true},
+		{name: "inside", start: at(10), end: at(11), from: at(9), to: at(12), want: true},
+		{name: "overlaps the start", start: at(8), end: at(10), from: at(9), want: true},
+		{name: "overlaps the end", start: at(11), end: at(13), to: at(12), want: true},
+		{name: "bounds are inclusive", start: at(12), end: at(12), from: at(12), to: at(12), want: true},
+		{name: "before", start: at(7), end: at(8), from: at(9), want: false},
+		{name: "after", start: at(13), end: at(14), to: at(12), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := inWindow(tt.start, tt.end, tt.from, tt.to); got != tt.want {
+			t.Errorf("%s: inWindow() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const currentResponse = `{
+	"location": {"name": "Barcelona", "country": "Spain", "tz_id": "Europe/Madrid", "localtime": "2025-06-01 10:00"},
+	"current": {
+		"last_updated": "2025-06-01 09:45", "temp_c": 21.5, "temp_f": 70.7, "feelslike_c": 22.1, "feelslike_f": 71.8,
+		"condition": {"text": "Partly cloudy"}, "wind_kph": 14.4, "wind_mph": 8.9, "wind_dir": "SSW",
+		"gust_kph": 20.2, "gust_mph": 12.5, "humidity": 64, "cloud": 25, "precip_mm": 0.1, "precip_in": 0.01,
+		"uv": 6, "vis_km": 10, "vis_miles": 6.2
+	}
+}`
+
+func TestWeatherTool(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		args string
+		want currentConditions
+	}{
+		{
+			name: "metric by default",
+			args: `{"location": "Barcelona"}`,
+			want: currentConditions{
+				Location: "Barcelona, Spain", LocalTime: "2025-06-01 10:00", LastUpdated: "2025-06-01 09:45", Condition: "Partly cloudy",
+				Temperature: "21.5 °C", FeelsLike: "22.1 °C", Wind: "14.4 km/h SSW", Gusts: "20.2 km/h", Humidity: "64%", Cloud: "25%",
+				Precipitation: "0.1 mm", UV: 6, Visibility: "10.0 km",
+			},
+		},
+		{
+			name: "imperial",
+			args: `{"location": "Barcelona", "units": "Imperial"}`,
+			want: currentConditions{
+				Location: "Barcelona, Spain", LocalTime: "2025-06-01 10:00", LastUpdated: "2025-06-01 09:45", Condition: "Partly cloudy",
+				Temperature: "70.7 °F", FeelsLike: "71.8 °F", Wind: "8.9 mph SSW", Gusts: "12.5 mph", Humidity: "64%", Cloud: "25%",
+				Precipitation: "0.01 in", UV: 6, Visibility: "6.2 mi",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := weatherClient(t, currentResponse, func(path string, query url.Values) {
+				if !strings.HasSuffix(path, "/current.json") || query.Get("q") != "Barcelona" {
+					t.Errorf("unexpected request %s?%s", path, query.Encode())
+				}
+			})
+
+			out, err := NewWeatherTool(client).Execute(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got currentConditions
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("expected JSON conditions, got %q", out)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("conditions mismatch (-got +want):\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	t.Run("unknown units are reported to the model", func(t *testing.T) {
+		client := weatherClient(t, currentResponse, func(string, url.Values) {
+			t.Error("unexpected request")
+		})
+
+		out, err := NewWeatherTool(client).Execute(ctx, `{"location": "Barcelona", "units": "kelvin"}`)
+		if err != nil || out != `unknown units "kelvin", expected metric or imperial` {
+			t.Errorf("expected unknown units message, got %q, err %v", out, err)
+		}
+	})
+}
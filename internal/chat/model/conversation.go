@@ -13,7 +13,33 @@ type Conversation struct {
 	Title     string             `bson:"subject"`
 	CreatedAt time.Time          `bson:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
-	Messages  []*Message         `bson:"messages"`
+	Messages  []*Message         `bson:"messages,omitempty"`
+
+	// OwnerID is the user who started the conversation. Conversations stored before authentication have none.
+	OwnerID string `bson:"owner_id,omitempty"`
+
+	// TenantID is the brand the conversation was started for, empty without tenants.
+	TenantID string `bson:"tenant_id,omitempty"`
+
+	// Version is incremented on every update, which only applies to the version it was read at. Conversations stored
+	// before versioning was introduced have version 0.
+	Version int64 `bson:"version"`
+
+	// Summary condenses the older messages of long conversations, it is nil until the conversation is summarized.
+	Summary *Summary `bson:"summary,omitempty"`
+
+	// Usage totals the completions of the conversation: its messages, title and summaries.
+	Usage Usage `bson:"usage"`
+}
+
+// Summary is a rolling summary of the messages of a conversation, up to and including the message Through.
+type Summary struct {
+	Text      string             `bson:"text"`
+	Through   primitive.ObjectID `bson:"through"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+
+	// Usage is the completion that generated the summary, which SetSummary adds to the conversation usage.
+	Usage *Usage `bson:"-"`
 }
 
 func (c *Conversation) Proto() *pb.Conversation {
@@ -21,6 +47,11 @@ func (c *Conversation) Proto() *pb.Conversation {
 		Id:        c.ID.Hex(),
 		Title:     c.Title,
 		Timestamp: timestamppb.New(c.UpdatedAt),
+		Usage:     c.Usage.Proto(),
+	}
+
+	if c.Summary != nil {
+		proto.Summary = c.Summary.Text
 	}
 
 	for _, m := range c.Messages {
@@ -0,0 +1,365 @@
+package model
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryStore is a goroutine-safe in-memory ConversationStore for tests and local demos. Conversations are copied on
+// the way in and out, so callers never share state with the store.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	conversations map[primitive.ObjectID]*Conversation
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{conversations: map[primitive.ObjectID]*Conversation{}}
+}
+
+func (s *MemoryStore) CreateConversation(ctx context.Context, c *Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := authorize(ctx, c); err != nil {
+		return err
+	}
+
+	if _, ok := s.conversations[c.ID]; ok {
+		return twirp.AlreadyExists.Error("conversation already exists")
+	}
+
+	s.conversations[c.ID] = c.clone()
+	return nil
+}
+
+func (s *MemoryStore) DescribeConversation(ctx context.Context, id string) (*Conversation, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, twirp.NotFoundError("invalid conversation ID")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, err := s.owned(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.clone(), nil
+}
+
+func (s *MemoryStore) ListConversations(ctx context.Context, q ListQuery) ([]*Conversation, string, error) {
+	after, err := decodeCursor(q.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	title := strings.ToLower(q.TitleContains)
+
+	s.mu.RLock()
+	var items []*Conversation
+	for _, c := range s.conversations {
+		switch {
+		case !inScope(ctx, c):
+		case !q.CreatedAfter.IsZero() && c.CreatedAt.Before(q.CreatedAfter):
+		case !q.CreatedBefore.IsZero() && !c.CreatedAt.Before(q.CreatedBefore):
+		case title != "" && !strings.Contains(strings.ToLower(c.Title), title):
+		case after != nil && !before(c, after):
+		default:
+			items = append(items, c.header())
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(items, func(a, b *Conversation) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.Hex(), a.ID.Hex())
+	})
+
+	if limit := q.limit(); len(items) > limit {
+		items = items[:limit]
+		return items, encodeCursor(items[limit-1].CreatedAt, items[limit-1].ID), nil
+	}
+
+	return items, "", nil
+}
+
+func (s *MemoryStore) UpdateConversation(ctx context.Context, c *Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(ctx, c); err != nil {
+		return err
+	}
+
+	// The summary is maintained by SetSummary.
+	stored := c.clone()
+	stored.Version++
+	stored.Summary = s.conversations[c.ID].Summary
+	s.conversations[c.ID] = stored
+
+	c.Version = stored.Version
+	return nil
+}
+
+func (s *MemoryStore) AppendMessages(ctx context.Context, c *Conversation, messages ...*Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(ctx, c); err != nil {
+		return err
+	}
+
+	stored := s.conversations[c.ID]
+	for _, m := range messages {
+		stored.Messages = append(stored.Messages, m.clone())
+	}
+
+	usage := sumUsage(messages)
+	stored.Usage.Add(&usage)
+	stored.UpdatedAt = c.UpdatedAt
+	stored.Version++
+
+	c.Usage.Add(&usage)
+	c.Version = stored.Version
+	return nil
+}
+
+// check returns the error updating c fails with, if any. The lock must be held.
+func (s *MemoryStore) check(ctx context.Context, c *Conversation) error {
+	stored, err := s.owned(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	if stored.Version != c.Version {
+		return errConflict
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteConversation(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.owned(ctx, oid); err != nil {
+		return err
+	}
+
+	delete(s.conversations, oid)
+	return nil
+}
+
+// SearchConversations ranks conversations by the number of matching messages, a matching title counting as one.
+func (s *MemoryStore) SearchConversations(ctx context.Context, query string, limit int) ([]*SearchResult, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	type scored struct {
+		res   *SearchResult
+		score int
+	}
+
+	var matches []scored
+
+	s.mu.RLock()
+	for _, c := range s.conversations {
+		if !inScope(ctx, c) {
+			continue
+		}
+
+		res := newSearchResult(c.clone(), terms)
+
+		score := len(res.MessageIDs)
+		if _, ok := highlight(c.Title, terms); ok {
+			score++
+		}
+
+		if score > 0 {
+			matches = append(matches, scored{res: res, score: score})
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(matches, func(a, b scored) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		return b.res.Conversation.CreatedAt.Compare(a.res.Conversation.CreatedAt)
+	})
+
+	var items []*SearchResult
+	for _, m := range matches[:min(len(matches), limit, MaxPageSize)] {
+		items = append(items, m.res)
+	}
+
+	return items, nil
+}
+
+func (s *MemoryStore) ListMessages(ctx context.Context, conversationID string, q MessageQuery) ([]*Message, string, error) {
+	after, err := decodeCursor(q.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return nil, "", twirp.NotFoundError("invalid conversation ID")
+	}
+
+	s.mu.RLock()
+	c, err := s.owned(ctx, oid)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, "", err
+	}
+
+	var items []*Message
+	for _, m := range c.Messages {
+		if after == nil || after.follows(m.CreatedAt, m.ID) {
+			items = append(items, m.clone())
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortStableFunc(items, func(a, b *Message) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.Hex(), b.ID.Hex())
+	})
+
+	if limit := q.limit(); len(items) > limit {
+		items = items[:limit]
+		return items, encodeCursor(items[limit-1].CreatedAt, items[limit-1].ID), nil
+	}
+
+	return items, "", nil
+}
+
+func (s *MemoryStore) PinMessage(ctx context.Context, conversationID, messageID string, pinned bool) error {
+	cid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	mid, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return twirp.NotFoundError("invalid message ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.owned(ctx, cid)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range c.Messages {
+		if m.ID == mid {
+			m.Pinned = pinned
+			return nil
+		}
+	}
+
+	return twirp.NotFoundError("message not found")
+}
+
+func (s *MemoryStore) SetSummary(ctx context.Context, conversationID string, summary *Summary) error {
+	oid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.owned(ctx, oid)
+	if err != nil {
+		return err
+	}
+
+	copied := *summary
+	copied.Usage = nil
+
+	c.Summary = &copied
+	c.Usage.Add(summary.Usage)
+	return nil
+}
+
+// owned returns the stored conversation when the owner of the context may access it. The lock must be held.
+func (s *MemoryStore) owned(ctx context.Context, id primitive.ObjectID) (*Conversation, error) {
+	c, ok := s.conversations[id]
+	if !ok {
+		return nil, twirp.NotFoundError("conversation not found")
+	}
+
+	if err := authorize(ctx, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// before reports whether c is listed after the cursor, i.e. it is older, or as old with a lower ID.
+func before(c *Conversation, after *cursor) bool {
+	if c.CreatedAt.Equal(after.CreatedAt) {
+		return c.ID.Hex() < after.ID.Hex()
+	}
+
+	return c.CreatedAt.Before(after.CreatedAt)
+}
+
+// clone returns a deep copy of the conversation.
+func (c *Conversation) clone() *Conversation {
+	out := c.header()
+	for _, m := range c.Messages {
+		out.Messages = append(out.Messages, m.clone())
+	}
+
+	return out
+}
+
+// header returns a copy of the conversation without its messages.
+func (c *Conversation) header() *Conversation {
+	out := *c
+	out.Messages = nil
+	if c.Summary != nil {
+		summary := *c.Summary
+		out.Summary = &summary
+	}
+
+	return &out
+}
+
+func (m *Message) clone() *Message {
+	out := *m
+	out.ToolCalls = nil
+	for _, call := range m.ToolCalls {
+		cp := *call
+		out.ToolCalls = append(out.ToolCalls, &cp)
+	}
+
+	return &out
+}
@@ -14,13 +14,56 @@ type Message struct {
 	Content   string             `bson:"content"`
 	CreatedAt time.Time          `bson:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
+
+	// ToolCalls are the tools requested by an assistant message.
+	ToolCalls []*ToolCall `bson:"tool_calls,omitempty"`
+
+	// ToolCallID and ToolName identify the call a tool message answers.
+	ToolCallID string `bson:"tool_call_id,omitempty"`
+	ToolName   string `bson:"tool_name,omitempty"`
+
+	// Pinned messages are kept in the context sent to the model when older messages are dropped.
+	Pinned bool `bson:"pinned,omitempty"`
+
+	// Model and Usage record the completion that generated an assistant message.
+	Model string `bson:"model,omitempty"`
+	Usage *Usage `bson:"usage,omitempty"`
 }
 
 func (m *Message) Proto() *pb.Conversation_Message {
-	return &pb.Conversation_Message{
-		Id:        m.ID.Hex(),
-		Role:      m.Role.Proto(),
-		Content:   m.Content,
-		Timestamp: timestamppb.New(m.CreatedAt),
+	proto := &pb.Conversation_Message{
+		Id:         m.ID.Hex(),
+		Role:       m.Role.Proto(),
+		Content:    m.Content,
+		Timestamp:  timestamppb.New(m.CreatedAt),
+		ToolCallId: m.ToolCallID,
+		ToolName:   m.ToolName,
+		Pinned:     m.Pinned,
+		Model:      m.Model,
+	}
+
+	if m.Usage != nil {
+		proto.Usage = m.Usage.Proto()
+	}
+
+	for _, c := range m.ToolCalls {
+		proto.ToolCalls = append(proto.ToolCalls, c.Proto())
+	}
+
+	return proto
+}
+
+// ToolCall is a tool invocation requested by the assistant, with JSON encoded arguments.
+type ToolCall struct {
+	ID        string `bson:"id"`
+	Name      string `bson:"name"`
+	Arguments string `bson:"arguments"`
+}
+
+func (c *ToolCall) Proto() *pb.Conversation_ToolCall {
+	return &pb.Conversation_ToolCall{
+		Id:        c.ID,
+		Name:      c.Name,
+		Arguments: c.Arguments,
 	}
 }
@@ -0,0 +1,33 @@
+-- Timestamps are stored as Unix nanoseconds, so they sort and compare the same way in every database.
+
+CREATE TABLE conversations (
+    id         TEXT PRIMARY KEY,
+    title      TEXT   NOT NULL,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+CREATE INDEX conversations_created_at ON conversations (created_at, id);
+
+CREATE TABLE messages (
+    id              TEXT PRIMARY KEY,
+    conversation_id TEXT    NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    position        INTEGER NOT NULL,
+    role            TEXT    NOT NULL,
+    content         TEXT    NOT NULL,
+    tool_call_id    TEXT    NOT NULL DEFAULT '',
+    tool_name       TEXT    NOT NULL DEFAULT '',
+    created_at      BIGINT  NOT NULL,
+    updated_at      BIGINT  NOT NULL
+);
+
+CREATE INDEX messages_conversation_position ON messages (conversation_id, position);
+
+CREATE TABLE tool_calls (
+    message_id TEXT    NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    id         TEXT    NOT NULL,
+    name       TEXT    NOT NULL,
+    arguments  TEXT    NOT NULL,
+    PRIMARY KEY (message_id, position)
+);
@@ -0,0 +1,3 @@
+-- Conversations are updated optimistically, each update increments the version.
+
+ALTER TABLE conversations ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Messages are paginated oldest first, ties on the creation time broken by ID.
+
+CREATE INDEX messages_conversation_created_at ON messages (conversation_id, created_at, id);
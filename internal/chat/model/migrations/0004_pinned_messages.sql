@@ -0,0 +1,3 @@
+-- Pinned messages are always part of the context sent to the model.
+
+ALTER TABLE messages ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+-- Long conversations keep a rolling summary of their older messages, up to and including the message summary_through.
+
+ALTER TABLE conversations ADD COLUMN summary TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN summary_through TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN summary_updated_at BIGINT NOT NULL DEFAULT 0;
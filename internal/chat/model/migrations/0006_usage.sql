@@ -0,0 +1,12 @@
+-- Token usage and estimated cost of each assistant message, and their running totals on the conversation.
+
+ALTER TABLE conversations ADD COLUMN prompt_tokens BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE conversations ADD COLUMN completion_tokens BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE conversations ADD COLUMN total_tokens BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE conversations ADD COLUMN cost DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE messages ADD COLUMN model TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN prompt_tokens BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN completion_tokens BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN total_tokens BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN cost DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Conversations belong to the user who started them, and are listed per owner.
+
+ALTER TABLE conversations ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX conversations_owner_created_at ON conversations (owner_id, created_at, id);
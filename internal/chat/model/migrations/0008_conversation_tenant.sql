@@ -0,0 +1,6 @@
+-- Conversations belong to a tenant, and are listed per tenant and owner.
+
+ALTER TABLE conversations ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+
+DROP INDEX conversations_owner_created_at;
+CREATE INDEX conversations_tenant_owner_created_at ON conversations (tenant_id, owner_id, created_at, id);
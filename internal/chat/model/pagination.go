@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListQuery filters and paginates ListConversations. Zero values disable the corresponding filter.
+type ListQuery struct {
+	PageSize      int
+	PageToken     string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	TitleContains string
+}
+
+// limit returns the effective page size of the query.
+func (q ListQuery) limit() int {
+	return pageLimit(q.PageSize)
+}
+
+// MessageQuery paginates ListMessages.
+type MessageQuery struct {
+	PageSize  int
+	PageToken string
+}
+
+// limit returns the effective page size of the query.
+func (q MessageQuery) limit() int {
+	return pageLimit(q.PageSize)
+}
+
+func pageLimit(size int) int {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+
+	return min(size, MaxPageSize)
+}
+
+// cursor points to the last item of a page. Conversations are listed newest first and messages oldest first, ties on
+// the creation time are broken by ID.
+type cursor struct {
+	CreatedAt time.Time          `json:"c"`
+	ID        primitive.ObjectID `json:"i"`
+}
+
+func encodeCursor(createdAt time.Time, id primitive.ObjectID) string {
+	b, _ := json.Marshal(cursor{CreatedAt: createdAt, ID: id})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses a page token, returning nil for an empty token.
+func decodeCursor(token string) (*cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var c cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+
+	if err != nil || c.ID.IsZero() {
+		return nil, twirp.InvalidArgumentError("page_token", "is not a valid page token")
+	}
+
+	return &c, nil
+}
+
+// follows reports whether an item created at createdAt with the given ID is listed after the cursor in ascending
+// order, i.e. it is newer, or as new with a higher ID.
+func (c *cursor) follows(createdAt time.Time, id primitive.ObjectID) bool {
+	if createdAt.Equal(c.CreatedAt) {
+		return id.Hex() > c.ID.Hex()
+	}
+
+	return createdAt.After(c.CreatedAt)
+}
@@ -1,8 +1,14 @@
 package model
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
 
 	"github.com/twitchtv/twirp"
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,6 +19,7 @@ import (
 
 const (
 	conversationCollection = "conversations"
+	messageCollection      = "messages"
 )
 
 type Repository struct {
@@ -25,11 +32,161 @@ func New(conn *mongo.Database) *Repository {
 	}
 }
 
-func (r *Repository) CreateConversation(ctx context.Context, c *Conversation) error {
-	_, err := r.conn.Collection(conversationCollection).InsertOne(ctx, c)
+// EnsureIndexes creates the indexes the repository relies on, it is safe to call on every start.
+func (r *Repository) EnsureIndexes(ctx context.Context) error {
+	if err := r.dropStaleTextIndex(ctx); err != nil {
+		return err
+	}
+
+	_, err := r.conn.Collection(conversationCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "subject", Value: "text"}},
+		Options: options.Index().SetName("conversation_text"),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn.Collection(conversationCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("conversation_tenant_owner_created_at"),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn.Collection(messageCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "conversation_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("conversation_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "content", Value: "text"}},
+			Options: options.Index().SetName("message_text"),
+		},
+	})
+
 	return err
 }
 
+// dropStaleTextIndex drops the text index of conversations created by earlier versions, which also covered the
+// embedded messages. A collection has a single text index, it is then rebuilt on the title only.
+func (r *Repository) dropStaleTextIndex(ctx context.Context) error {
+	cursor, err := r.conn.Collection(conversationCollection).Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var indexes []struct {
+		Name    string         `bson:"name"`
+		Weights map[string]any `bson:"weights"`
+	}
+
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if _, ok := index.Weights["messages.content"]; ok {
+			if _, err := r.conn.Collection(conversationCollection).Indexes().DropOne(ctx, index.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MigrateMessages moves the messages embedded in conversation documents, as stored by earlier versions, to the
+// messages collection, returning the number of migrated conversations. It can be interrupted and run again.
+func (r *Repository) MigrateMessages(ctx context.Context) (int, error) {
+	cursor, err := r.conn.Collection(conversationCollection).
+		Find(ctx, bson.D{{Key: "messages", Value: bson.D{{Key: "$exists", Value: true}}}})
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	migrated := 0
+
+	for cursor.Next(ctx) {
+		var c Conversation
+
+		if err := cursor.Decode(&c); err != nil {
+			return migrated, err
+		}
+
+		// Messages are upserted, as a previous run may have stopped before removing them from the conversation.
+		if len(c.Messages) > 0 {
+			var writes []mongo.WriteModel
+			for _, m := range c.Messages {
+				writes = append(writes, mongo.NewReplaceOneModel().
+					SetFilter(bson.D{{Key: "_id", Value: m.ID}}).
+					SetReplacement(storedMessage{ConversationID: c.ID, Message: m}).
+					SetUpsert(true))
+			}
+
+			if _, err := r.conn.Collection(messageCollection).BulkWrite(ctx, writes); err != nil {
+				return migrated, fmt.Errorf("failed to migrate messages of conversation %s: %w", c.ID.Hex(), err)
+			}
+		}
+
+		if _, err := r.conn.Collection(conversationCollection).UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: c.ID}},
+			bson.D{{Key: "$unset", Value: bson.D{{Key: "messages", Value: ""}}}}); err != nil {
+			return migrated, err
+		}
+
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
+
+// PendingMigration reports whether conversations still embed their messages, see MigrateMessages. The messages of
+// such conversations are not read by the repository, which must not serve them until they are migrated.
+func (r *Repository) PendingMigration(ctx context.Context) (bool, error) {
+	err := r.conn.Collection(conversationCollection).FindOne(ctx,
+		bson.D{{Key: "messages", Value: bson.D{{Key: "$exists", Value: true}}}},
+		options.FindOne().SetProjection(bson.D{{Key: "_id", Value: 1}})).Err()
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// storedMessage is a message document of the messages collection.
+type storedMessage struct {
+	ConversationID primitive.ObjectID `bson:"conversation_id"`
+	*Message       `bson:",inline"`
+}
+
+// CreateConversation inserts the messages before the conversation, so that it is never visible without them. The
+// writes are ordered rather than run in a transaction, as MongoDB only supports those on replica sets.
+func (r *Repository) CreateConversation(ctx context.Context, c *Conversation) error {
+	if err := authorize(ctx, c); err != nil {
+		return err
+	}
+
+	if err := r.insertMessages(ctx, c.ID, c.Messages); err != nil {
+		return err
+	}
+
+	if _, err := r.conn.Collection(conversationCollection).InsertOne(ctx, c.header()); err != nil {
+		r.deleteMessages(ctx, c.Messages)
+		return err
+	}
+
+	return nil
+}
+
 func (r *Repository) DescribeConversation(ctx context.Context, id string) (*Conversation, error) {
 	var c Conversation
 
@@ -47,18 +204,239 @@ func (r *Repository) DescribeConversation(ctx context.Context, id string) (*Conv
 		return nil, err
 	}
 
+	if err := authorize(ctx, &c); err != nil {
+		return nil, err
+	}
+
+	c.Messages, err = r.findMessages(ctx, bson.D{{Key: "conversation_id", Value: oid}}, options.Find())
+	if err != nil {
+		return nil, err
+	}
+
 	return &c, nil
 }
 
-func (r *Repository) ListConversations(ctx context.Context) ([]*Conversation, error) {
+func (r *Repository) ListMessages(ctx context.Context, conversationID string, q MessageQuery) ([]*Message, string, error) {
+	after, err := decodeCursor(q.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return nil, "", twirp.NotFoundError("invalid conversation ID")
+	}
+
+	if err := r.checkAccess(ctx, oid); err != nil {
+		return nil, "", err
+	}
+
+	filter := bson.D{{Key: "conversation_id", Value: oid}}
+	if after != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "created_at", Value: bson.D{{Key: "$gt", Value: after.CreatedAt}}}},
+			bson.D{{Key: "created_at", Value: after.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: after.ID}}}},
+		}})
+	}
+
+	// One extra message is fetched to know whether there is a next page.
+	limit := q.limit()
+
+	items, err := r.findMessages(ctx, filter, options.Find().SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(items) <= limit {
+		return items, "", nil
+	}
+
+	items = items[:limit]
+	return items, encodeCursor(items[limit-1].CreatedAt, items[limit-1].ID), nil
+}
+
+func (r *Repository) PinMessage(ctx context.Context, conversationID, messageID string, pinned bool) error {
+	cid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	mid, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return twirp.NotFoundError("invalid message ID")
+	}
+
+	if err := r.checkAccess(ctx, cid); err != nil {
+		return err
+	}
+
+	res, err := r.conn.Collection(messageCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: mid}, {Key: "conversation_id", Value: cid}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "pinned", Value: pinned}}}})
+
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return twirp.NotFoundError("message not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) SetSummary(ctx context.Context, conversationID string, summary *Summary) error {
+	oid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	if err := r.checkAccess(ctx, oid); err != nil {
+		return err
+	}
+
+	res, err := r.conn.Collection(conversationCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: oid}},
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "summary", Value: summary}}},
+			{Key: "$inc", Value: incUsage(cmp.Or(summary.Usage, &Usage{}))},
+		})
+
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	return nil
+}
+
+// checkAccess returns the error accessing the conversation fails with in the scope of the context, if any.
+func (r *Repository) checkAccess(ctx context.Context, id primitive.ObjectID) error {
+	var c Conversation
+
+	err := r.conn.Collection(conversationCollection).
+		FindOne(ctx, bson.D{{Key: "_id", Value: id}}, options.FindOne().SetProjection(bson.D{{Key: "owner_id", Value: 1}, {Key: "tenant_id", Value: 1}})).
+		Decode(&c)
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return authorize(ctx, &c)
+}
+
+// findMessages returns the messages matching the filter, oldest first.
+func (r *Repository) findMessages(ctx context.Context, filter bson.D, opts *options.FindOptions) ([]*Message, error) {
+	opts.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.conn.Collection(messageCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var items []*Message
+
+	for cursor.Next(ctx) {
+		m := storedMessage{Message: &Message{}}
+
+		if err := cursor.Decode(&m); err != nil {
+			return nil, err
+		}
+
+		items = append(items, m.Message)
+	}
+
+	return items, cursor.Err()
+}
+
+func (r *Repository) insertMessages(ctx context.Context, conversationID primitive.ObjectID, messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(messages))
+	for i, m := range messages {
+		docs[i] = storedMessage{ConversationID: conversationID, Message: m}
+	}
+
+	_, err := r.conn.Collection(messageCollection).InsertMany(ctx, docs)
+	return err
+}
+
+// deleteMessages removes messages inserted for a write that failed. Failing to do so is only logged, as the write
+// error is more relevant to the caller.
+func (r *Repository) deleteMessages(ctx context.Context, messages []*Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	ids := make(bson.A, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+
+	if _, err := r.conn.Collection(messageCollection).DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete the messages of a failed write", "error", err)
+	}
+}
+
+// ListConversations returns a page of conversations matching the query, newest first, without their messages, along
+// with the token of the next page, which is empty on the last page.
+func (r *Repository) ListConversations(ctx context.Context, q ListQuery) ([]*Conversation, string, error) {
+	after, err := decodeCursor(q.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := scopeFilter(ctx)
+
+	created := bson.D{}
+	if !q.CreatedAfter.IsZero() {
+		created = append(created, bson.E{Key: "$gte", Value: q.CreatedAfter})
+	}
+	if !q.CreatedBefore.IsZero() {
+		created = append(created, bson.E{Key: "$lt", Value: q.CreatedBefore})
+	}
+	if len(created) > 0 {
+		filter = append(filter, bson.E{Key: "created_at", Value: created})
+	}
+
+	if q.TitleContains != "" {
+		filter = append(filter, bson.E{Key: "subject", Value: primitive.Regex{Pattern: regexp.QuoteMeta(q.TitleContains), Options: "i"}})
+	}
+
+	if after != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "created_at", Value: bson.D{{Key: "$lt", Value: after.CreatedAt}}}},
+			bson.D{{Key: "created_at", Value: after.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$lt", Value: after.ID}}}},
+		}})
+	}
+
+	limit := q.limit()
+
+	// One extra conversation is fetched to know whether there is a next page.
 	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}})
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		// Conversations stored before MigrateMessages still embed their messages.
+		SetProjection(bson.D{{Key: "messages", Value: 0}}).
+		SetLimit(int64(limit + 1))
 
 	cursor, err := r.conn.Collection(conversationCollection).
-		Find(ctx, map[string]any{}, opts)
+		Find(ctx, filter, opts)
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	defer func() {
@@ -71,36 +449,340 @@ func (r *Repository) ListConversations(ctx context.Context) ([]*Conversation, er
 		var c Conversation
 
 		if err := cursor.Decode(&c); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		items = append(items, &c)
 	}
 
 	if err := cursor.Err(); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return items, nil
+	if len(items) <= limit {
+		return items, "", nil
+	}
+
+	items = items[:limit]
+	return items, encodeCursor(items[limit-1].CreatedAt, items[limit-1].ID), nil
 }
 
+// UpdateConversation stores the conversation and replaces its messages. Once the version is claimed, the new messages
+// are upserted before the others are deleted, so that the conversation never loses its messages midway.
 func (r *Repository) UpdateConversation(ctx context.Context, c *Conversation) error {
-	_, err := r.conn.Collection(conversationCollection).UpdateOne(ctx,
-		map[string]any{"_id": c.ID},
-		map[string]any{"$set": c})
+	if err := r.checkAccess(ctx, c.ID); err != nil {
+		return err
+	}
 
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return twirp.NotFoundError("conversation not found")
+	next := c.header()
+	next.Version++
+
+	// The summary is maintained by SetSummary.
+	next.Summary = nil
+
+	res, err := r.conn.Collection(conversationCollection).UpdateOne(ctx,
+		versionFilter(c),
+		map[string]any{"$set": next})
+
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return r.updateError(ctx, c)
 	}
 
+	c.Version = next.Version
+
+	ids := bson.A{}
+	var writes []mongo.WriteModel
+	for _, m := range c.Messages {
+		ids = append(ids, m.ID)
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: m.ID}}).
+			SetReplacement(storedMessage{ConversationID: c.ID, Message: m}).
+			SetUpsert(true))
+	}
+
+	if len(writes) > 0 {
+		if _, err := r.conn.Collection(messageCollection).BulkWrite(ctx, writes); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.conn.Collection(messageCollection).DeleteMany(ctx, bson.D{
+		{Key: "conversation_id", Value: c.ID},
+		{Key: "_id", Value: bson.D{{Key: "$nin", Value: ids}}},
+	})
+
 	return err
 }
 
+// AppendMessages inserts the messages and then claims the next version of the conversation, so that a version is
+// never claimed without its messages. The messages of the turns losing the claim to a concurrent one are deleted.
+func (r *Repository) AppendMessages(ctx context.Context, c *Conversation, messages ...*Message) error {
+	if err := r.checkAccess(ctx, c.ID); err != nil {
+		return err
+	}
+
+	if err := r.insertMessages(ctx, c.ID, messages); err != nil {
+		return err
+	}
+
+	usage := sumUsage(messages)
+
+	res, err := r.conn.Collection(conversationCollection).UpdateOne(ctx,
+		versionFilter(c),
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: c.UpdatedAt}}},
+			{Key: "$inc", Value: append(incUsage(&usage), bson.E{Key: "version", Value: 1})},
+		})
+
+	if err != nil {
+		r.deleteMessages(ctx, messages)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		r.deleteMessages(ctx, messages)
+		return r.updateError(ctx, c)
+	}
+
+	c.Version++
+	c.Usage.Add(&usage)
+	return nil
+}
+
+// incUsage returns the $inc fields adding the usage to the conversation totals.
+func incUsage(u *Usage) bson.D {
+	return bson.D{
+		{Key: "usage.prompt_tokens", Value: u.PromptTokens},
+		{Key: "usage.completion_tokens", Value: u.CompletionTokens},
+		{Key: "usage.total_tokens", Value: u.TotalTokens},
+		{Key: "usage.cost", Value: u.Cost},
+	}
+}
+
+// scopeFilter matches the conversations in the scope of the context, see WithOwner and WithTenant.
+func scopeFilter(ctx context.Context) bson.D {
+	filter := bson.D{}
+
+	if tenant := TenantFrom(ctx); tenant != "" {
+		filter = append(filter, bson.E{Key: "tenant_id", Value: tenant})
+	}
+
+	if owner := OwnerFrom(ctx); owner != "" {
+		filter = append(filter, bson.E{Key: "owner_id", Value: owner})
+	}
+
+	return filter
+}
+
+// versionFilter matches the conversation at the version it was read at. Documents stored before versioning have no
+// version field, which matches version 0.
+func versionFilter(c *Conversation) bson.D {
+	if c.Version == 0 {
+		return bson.D{{Key: "_id", Value: c.ID}, {Key: "version", Value: bson.D{{Key: "$in", Value: bson.A{0, nil}}}}}
+	}
+
+	return bson.D{{Key: "_id", Value: c.ID}, {Key: "version", Value: c.Version}}
+}
+
+// updateError tells apart a deleted conversation from a concurrently updated one, once a versioned update matched no
+// document.
+func (r *Repository) updateError(ctx context.Context, c *Conversation) error {
+	n, err := r.conn.Collection(conversationCollection).CountDocuments(ctx, bson.D{{Key: "_id", Value: c.ID}})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	return errConflict
+}
+
 func (r *Repository) DeleteConversation(ctx context.Context, id string) error {
-	_, err := r.conn.Collection(conversationCollection).DeleteOne(ctx, map[string]any{"_id": id})
-	if errors.Is(err, mongo.ErrNoDocuments) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	if err := r.checkAccess(ctx, oid); err != nil {
+		return err
+	}
+
+	// Messages are deleted first, so that an interrupted deletion leaves no orphan messages, which would still be
+	// found by SearchConversations, and can be retried.
+	if _, err := r.conn.Collection(messageCollection).DeleteMany(ctx, bson.D{{Key: "conversation_id", Value: oid}}); err != nil {
+		return err
+	}
+
+	res, err := r.conn.Collection(conversationCollection).DeleteOne(ctx, map[string]any{"_id": oid})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
 		return twirp.NotFoundError("conversation not found")
 	}
 
-	return err
+	return nil
+}
+
+// SearchConversations returns up to limit conversations whose title or messages match the query, best matches first.
+// A conversation scores the text score of its title plus the scores of its matching messages.
+func (r *Repository) SearchConversations(ctx context.Context, query string, limit int) ([]*SearchResult, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	text := bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}}}
+	score := bson.D{{Key: "$meta", Value: "textScore"}}
+
+	titles, err := r.conn.Collection(conversationCollection).Find(ctx, append(scopeFilter(ctx), text...), options.Find().
+		SetProjection(bson.D{{Key: "score", Value: score}}).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(int64(limit)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[primitive.ObjectID]float64{}
+	if err := sumScores(ctx, titles, scores); err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: text}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$conversation_id"}, {Key: "score", Value: bson.D{{Key: "$sum", Value: score}}}}}},
+	}
+
+	// Messages do not hold the owner and tenant, they are looked up on their conversation.
+	if scope := scopeFilter(ctx); len(scope) > 0 {
+		match := bson.D{}
+		for _, e := range scope {
+			match = append(match, bson.E{Key: "conversation." + e.Key, Value: e.Value})
+		}
+
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: conversationCollection},
+				{Key: "localField", Value: "_id"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "conversation"},
+			}}},
+			bson.D{{Key: "$match", Value: match}},
+		)
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+
+	messages, err := r.conn.Collection(messageCollection).Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sumScores(ctx, messages, scores); err != nil {
+		return nil, err
+	}
+
+	ids := slices.Collect(maps.Keys(scores))
+	slices.SortFunc(ids, func(a, b primitive.ObjectID) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+
+	ids = ids[:min(len(ids), limit)]
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// Conversations deleted since they were found are left out.
+	headers, err := r.conn.Collection(conversationCollection).Find(ctx,
+		append(scopeFilter(ctx), bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}),
+		options.Find().SetProjection(bson.D{{Key: "messages", Value: 0}}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*Conversation
+	if err := headers.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	conversations := map[primitive.ObjectID]*Conversation{}
+	for _, c := range found {
+		conversations[c.ID] = c
+	}
+
+	// Only the matching messages of the conversations are fetched, all at once, for their snippets.
+	matches, err := r.conn.Collection(messageCollection).Find(ctx, append(bson.D{
+		{Key: "conversation_id", Value: bson.D{{Key: "$in", Value: ids}}},
+		{Key: "role", Value: bson.D{{Key: "$in", Value: bson.A{RoleUser, RoleAssistant}}}},
+	}, text...), options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = matches.Close(ctx)
+	}()
+
+	for matches.Next(ctx) {
+		m := storedMessage{Message: &Message{}}
+
+		if err := matches.Decode(&m); err != nil {
+			return nil, err
+		}
+
+		if c, ok := conversations[m.ConversationID]; ok {
+			c.Messages = append(c.Messages, m.Message)
+		}
+	}
+
+	if err := matches.Err(); err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query)
+	var items []*SearchResult
+
+	for _, id := range ids {
+		if c, ok := conversations[id]; ok {
+			items = append(items, newSearchResult(c, terms))
+		}
+	}
+
+	return items, nil
+}
+
+// sumScores adds the scores of the {_id, score} documents of the cursor to scores, and closes it.
+func sumScores(ctx context.Context, cursor *mongo.Cursor, scores map[primitive.ObjectID]float64) error {
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Score float64            `bson:"score"`
+		}
+
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		scores[doc.ID] += doc.Score
+	}
+
+	return cursor.Err()
 }
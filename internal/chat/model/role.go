@@ -7,6 +7,8 @@ type Role string
 const (
 	RoleUser      Role = "user"
 	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+	RoleSystem    Role = "system"
 )
 
 func (r Role) Proto() pb.Conversation_Role {
@@ -15,6 +17,10 @@ func (r Role) Proto() pb.Conversation_Role {
 		return pb.Conversation_USER
 	case RoleAssistant:
 		return pb.Conversation_ASSISTANT
+	case RoleTool:
+		return pb.Conversation_TOOL
+	case RoleSystem:
+		return pb.Conversation_SYSTEM
 	default:
 		return 0
 	}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"context"
+
+	"github.com/twitchtv/twirp"
+)
+
+type (
+	ownerKey  struct{}
+	tenantKey struct{}
+)
+
+// errForbidden is returned when a conversation belongs to another owner than the one of the context.
+var errForbidden = twirp.NewError(twirp.PermissionDenied, "conversation belongs to another user")
+
+// WithOwner scopes the stores to the conversations of the owner: the others are left out of lists and searches, and
+// accessing them fails with a twirp.PermissionDenied error. Without an owner, e.g. in maintenance commands, the stores
+// access every conversation.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// OwnerFrom returns the owner the context is scoped to, if any.
+func OwnerFrom(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	return owner
+}
+
+// WithTenant scopes the stores to the conversations of the tenant. Unlike those of other owners, the conversations of
+// other tenants are reported as not found, so that tenants do not learn about each other.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant the context is scoped to, if any.
+func TenantFrom(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// authorize returns the error accessing the conversation fails with in the scope of the context, if any.
+func authorize(ctx context.Context, c *Conversation) error {
+	if tenant := TenantFrom(ctx); tenant != "" && tenant != c.TenantID {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	if owner := OwnerFrom(ctx); owner != "" && owner != c.OwnerID {
+		return errForbidden
+	}
+
+	return nil
+}
+
+// inScope reports whether the conversation is listed in the scope of the context.
+func inScope(ctx context.Context, c *Conversation) bool {
+	return authorize(ctx, c) == nil
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// snippetRadius is the number of characters kept around the first match of a snippet.
+const snippetRadius = 60
+
+// SearchResult is a conversation matching a search, with the messages that matched it. The conversation is returned
+// without messages.
+type SearchResult struct {
+	Conversation *Conversation
+	MessageIDs   []primitive.ObjectID
+
+	// Snippets holds an excerpt of each matching message, with the matched words wrapped in **.
+	Snippets []string
+}
+
+// searchTerms splits a search query into lower case words.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), isSeparator)
+}
+
+// isSeparator reports whether r separates the words of queries and texts.
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// stem strips the common English inflections of a lower case word, roughly as the stemming of the MongoDB text index
+// does, so that "raining", "rained" and "rains" all stem to "rain". Stems are kept at least 3 letters long.
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "s"} {
+		s, ok := strings.CutSuffix(word, suffix)
+		if !ok || utf8.RuneCountInString(s) < 3 || (suffix == "s" && strings.HasSuffix(s, "s")) {
+			continue
+		}
+
+		// A doubled final consonant is undone, e.g. "running".
+		if n := len(s); suffix != "s" && s[n-1] == s[n-2] && !strings.ContainsRune("aeioulsz", rune(s[n-1])) {
+			s = s[:n-1]
+		}
+
+		word = s
+		break
+	}
+
+	if s, ok := strings.CutSuffix(word, "e"); ok && utf8.RuneCountInString(s) >= 3 {
+		word = s
+	}
+
+	return word
+}
+
+// newSearchResult collects the user and assistant messages of the conversation containing any of the terms, and
+// strips the messages from the conversation.
+func newSearchResult(c *Conversation, terms []string) *SearchResult {
+	res := &SearchResult{Conversation: c}
+
+	for _, m := range c.Messages {
+		if m.Role != RoleUser && m.Role != RoleAssistant {
+			continue
+		}
+
+		if snippet, ok := highlight(m.Content, terms); ok {
+			res.MessageIDs = append(res.MessageIDs, m.ID)
+			res.Snippets = append(res.Snippets, snippet)
+		}
+	}
+
+	c.Messages = nil
+	return res
+}
+
+// highlight returns an excerpt of text around the first word matching any term, with all matching words in the
+// excerpt wrapped in **. Text is split into words as queries are, and words match the terms sharing their stem, so
+// that "raining" matches "rain" but "Spain" does not match "in". It reports false when no word matches.
+func highlight(text string, terms []string) (string, bool) {
+	runes := []rune(text)
+
+	stems := map[string]bool{}
+	for _, t := range terms {
+		stems[stem(t)] = true
+	}
+
+	type match struct{ start, end int }
+	var matches []match
+
+	for i := 0; i < len(runes); {
+		if isSeparator(runes[i]) {
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !isSeparator(runes[end]) {
+			end++
+		}
+
+		if stems[stem(strings.ToLower(string(runes[i:end])))] {
+			matches = append(matches, match{i, end})
+		}
+
+		i = end
+	}
+
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	from := max(0, matches[0].start-snippetRadius)
+	to := min(len(runes), matches[0].end+snippetRadius)
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+
+	pos := from
+	for _, m := range matches {
+		if m.start < from || m.end > to {
+			continue
+		}
+
+		b.WriteString(string(runes[pos:m.start]))
+		b.WriteString("**" + string(runes[m.start:m.end]) + "**")
+		pos = m.end
+	}
+
+	b.WriteString(string(runes[pos:to]))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return strings.Join(strings.Fields(b.String()), " "), true
+}
@@ -0,0 +1,51 @@
+package model
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{
+			name:  "whole words only",
+			text:  "Will it rain in Spain?",
+			query: "in",
+			want:  "Will it rain **in** Spain?",
+		},
+		{
+			name:  "inflected words",
+			text:  "It was raining in Barcelona, and it rained again. Rains are rare.",
+			query: "rain",
+			want:  "It was **raining** in Barcelona, and it **rained** again. **Rains** are rare.",
+		},
+		{
+			name:  "inflected terms",
+			text:  "Heavy rain expected while running.",
+			query: "raining runs",
+			want:  "Heavy **rain** expected while **running**.",
+		},
+		{
+			name:  "case and punctuation",
+			text:  "Barcelona's weather: SUNNY!",
+			query: "sunny barcelona",
+			want:  "**Barcelona**'s weather: **SUNNY**!",
+		},
+		{
+			name:  "no match inside words",
+			text:  "Sunny in Madrid.",
+			query: "sun",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := highlight(tt.text, searchTerms(tt.query))
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("highlight(%q, %q) = %q, %t, want %q", tt.text, tt.query, got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,798 @@
+package model
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// SQLStore is a ConversationStore backed by a SQL database, storing conversations, messages and tool calls in
+// normalized tables. Queries stick to the SQL understood by both SQLite and PostgreSQL, the driver is registered by
+// the caller.
+type SQLStore struct {
+	db *sql.DB
+}
+
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Migrate applies the embedded migrations that have not been applied yet, it is safe to call on every start.
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(files)
+
+	for _, file := range files {
+		version := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+
+		var applied int
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, version).Scan(&applied); err != nil {
+			return err
+		}
+
+		if applied > 0 {
+			continue
+		}
+
+		script, err := migrations.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		err = s.tx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		})
+
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLStore) CreateConversation(ctx context.Context, c *Conversation) error {
+	if err := authorize(ctx, c); err != nil {
+		return err
+	}
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO conversations (id, title, created_at, updated_at, version, prompt_tokens, completion_tokens, total_tokens, cost,
+				owner_id, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			c.ID.Hex(), c.Title, c.CreatedAt.UnixNano(), c.UpdatedAt.UnixNano(), c.Version,
+			c.Usage.PromptTokens, c.Usage.CompletionTokens, c.Usage.TotalTokens, c.Usage.Cost, c.OwnerID, c.TenantID); err != nil {
+			return err
+		}
+
+		if c.Summary != nil {
+			if err := setSummary(ctx, tx, c.ID, c.Summary); err != nil {
+				return err
+			}
+		}
+
+		return insertMessages(ctx, tx, c.ID, 0, c.Messages)
+	})
+}
+
+func (s *SQLStore) DescribeConversation(ctx context.Context, id string) (*Conversation, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, twirp.NotFoundError("invalid conversation ID")
+	}
+
+	var c *Conversation
+
+	err = s.tx(ctx, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, `SELECT `+conversationColumns+` FROM conversations WHERE id = $1`, oid.Hex())
+
+		c, err = scanConversation(row)
+		if errors.Is(err, sql.ErrNoRows) {
+			return twirp.NotFoundError("conversation not found")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := authorize(ctx, c); err != nil {
+			return err
+		}
+
+		c.Messages, err = selectMessages(ctx, tx, "conversation_id = $1", "position", oid.Hex())
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s *SQLStore) ListConversations(ctx context.Context, q ListQuery) ([]*Conversation, string, error) {
+	after, err := decodeCursor(q.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var where []string
+	var args []any
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if tenant := TenantFrom(ctx); tenant != "" {
+		where = append(where, "tenant_id = "+arg(tenant))
+	}
+
+	if owner := OwnerFrom(ctx); owner != "" {
+		where = append(where, "owner_id = "+arg(owner))
+	}
+
+	if !q.CreatedAfter.IsZero() {
+		where = append(where, "created_at >= "+arg(q.CreatedAfter.UnixNano()))
+	}
+
+	if !q.CreatedBefore.IsZero() {
+		where = append(where, "created_at < "+arg(q.CreatedBefore.UnixNano()))
+	}
+
+	if q.TitleContains != "" {
+		where = append(where, "LOWER(title) LIKE "+arg("%"+escapeLike(strings.ToLower(q.TitleContains))+"%")+" ESCAPE '\\'")
+	}
+
+	if after != nil {
+		createdAt := arg(after.CreatedAt.UnixNano())
+		where = append(where, fmt.Sprintf("(created_at < %s OR (created_at = %s AND id < %s))", createdAt, createdAt, arg(after.ID.Hex())))
+	}
+
+	query := `SELECT ` + conversationColumns + ` FROM conversations`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// One extra conversation is fetched to know whether there is a next page.
+	limit := q.limit()
+	query += " ORDER BY created_at DESC, id DESC LIMIT " + arg(limit+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var items []*Conversation
+
+	for rows.Next() {
+		c, err := scanConversation(rows)
+		if err != nil {
+			return nil, "", err
+		}
+
+		items = append(items, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(items) <= limit {
+		return items, "", nil
+	}
+
+	items = items[:limit]
+	return items, encodeCursor(items[limit-1].CreatedAt, items[limit-1].ID), nil
+}
+
+func (s *SQLStore) UpdateConversation(ctx context.Context, c *Conversation) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if err := checkAccess(ctx, tx, c.ID); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			`UPDATE conversations SET title = $1, created_at = $2, updated_at = $3, version = version + 1,
+			prompt_tokens = $4, completion_tokens = $5, total_tokens = $6, cost = $7
+			WHERE id = $8 AND version = $9`,
+			c.Title, c.CreatedAt.UnixNano(), c.UpdatedAt.UnixNano(),
+			c.Usage.PromptTokens, c.Usage.CompletionTokens, c.Usage.TotalTokens, c.Usage.Cost, c.ID.Hex(), c.Version)
+		if err != nil {
+			return err
+		}
+
+		if err := updateError(ctx, tx, res, c); err != nil {
+			return err
+		}
+
+		if err := deleteMessages(ctx, tx, c.ID); err != nil {
+			return err
+		}
+
+		return insertMessages(ctx, tx, c.ID, 0, c.Messages)
+	}, func() { c.Version++ })
+}
+
+func (s *SQLStore) AppendMessages(ctx context.Context, c *Conversation, messages ...*Message) error {
+	usage := sumUsage(messages)
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if err := checkAccess(ctx, tx, c.ID); err != nil {
+			return err
+		}
+
+		// The conditional update locks the conversation row, so concurrent appends cannot interleave their positions.
+		res, err := tx.ExecContext(ctx,
+			`UPDATE conversations SET updated_at = $1, version = version + 1, `+addUsage(2)+` WHERE id = $6 AND version = $7`,
+			c.UpdatedAt.UnixNano(), usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, usage.Cost,
+			c.ID.Hex(), c.Version)
+		if err != nil {
+			return err
+		}
+
+		if err := updateError(ctx, tx, res, c); err != nil {
+			return err
+		}
+
+		var position int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COALESCE(MAX(position) + 1, 0) FROM messages WHERE conversation_id = $1`, c.ID.Hex()).Scan(&position); err != nil {
+			return err
+		}
+
+		return insertMessages(ctx, tx, c.ID, position, messages)
+	}, func() {
+		c.Version++
+		c.Usage.Add(&usage)
+	})
+}
+
+// addUsage returns the assignments adding the usage passed as the four parameters from $n to the conversation totals.
+// SQLite numbers the parameters in their order of appearance, so they must follow the ones before them in the query.
+func addUsage(n int) string {
+	return fmt.Sprintf("prompt_tokens = prompt_tokens + $%d, completion_tokens = completion_tokens + $%d, "+
+		"total_tokens = total_tokens + $%d, cost = cost + $%d", n, n+1, n+2, n+3)
+}
+
+func (s *SQLStore) DeleteConversation(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if err := checkAccess(ctx, tx, oid); err != nil {
+			return err
+		}
+
+		// Children are deleted explicitly, as SQLite only enforces foreign keys when asked to.
+		if err := deleteMessages(ctx, tx, oid); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM conversations WHERE id = $1`, oid.Hex())
+		if err != nil {
+			return err
+		}
+
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return twirp.NotFoundError("conversation not found")
+		}
+
+		return nil
+	})
+}
+
+// SearchConversations ranks conversations by the number of matching messages, a matching title counting as one.
+func (s *SQLStore) SearchConversations(ctx context.Context, query string, limit int) ([]*SearchResult, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var titles, contents []string
+	var args []any
+
+	for _, t := range terms {
+		args = append(args, "%"+escapeLike(t)+"%")
+		titles = append(titles, fmt.Sprintf("LOWER(c.title) LIKE $%d ESCAPE '\\'", len(args)))
+		contents = append(contents, fmt.Sprintf("LOWER(m.content) LIKE $%d ESCAPE '\\'", len(args)))
+	}
+
+	// SQLite numbers the parameters in their order of appearance, the scope must come after the terms.
+	scope := []string{"TRUE"}
+	if v := TenantFrom(ctx); v != "" {
+		args = append(args, v)
+		scope = append(scope, fmt.Sprintf("c.tenant_id = $%d", len(args)))
+	}
+
+	if v := OwnerFrom(ctx); v != "" {
+		args = append(args, v)
+		scope = append(scope, fmt.Sprintf("c.owner_id = $%d", len(args)))
+	}
+
+	args = append(args, min(limit, MaxPageSize))
+
+	var items []*SearchResult
+
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+			SELECT `+conversationColumns+` FROM conversations JOIN (
+				SELECT c.id AS match_id, (CASE WHEN %s THEN 1 ELSE 0 END) + COUNT(m.id) AS score
+				FROM conversations c
+				LEFT JOIN messages m ON m.conversation_id = c.id AND m.role IN ('user', 'assistant') AND (%s)
+				WHERE %s
+				GROUP BY c.id, c.title
+				HAVING (CASE WHEN %s THEN 1 ELSE 0 END) + COUNT(m.id) > 0
+			) r ON r.match_id = id
+			ORDER BY r.score DESC, created_at DESC
+			LIMIT $%d`,
+			strings.Join(titles, " OR "), strings.Join(contents, " OR "), strings.Join(scope, " AND "), strings.Join(titles, " OR "), len(args)), args...)
+
+		if err != nil {
+			return err
+		}
+
+		var ids []any
+		byID := map[string]*Conversation{}
+
+		for rows.Next() {
+			c, err := scanConversation(rows)
+			if err != nil {
+				_ = rows.Close()
+				return err
+			}
+
+			items = append(items, &SearchResult{Conversation: c})
+			ids = append(ids, c.ID.Hex())
+			byID[c.ID.Hex()] = c
+		}
+
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// The matching messages of all the conversations are fetched at once, the snippets only needing their content.
+		placeholders := make([]string, len(ids))
+		for i := range ids {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		}
+
+		messageArgs := ids
+		matches := make([]string, len(terms))
+		for i, t := range terms {
+			messageArgs = append(messageArgs, "%"+escapeLike(t)+"%")
+			matches[i] = fmt.Sprintf("LOWER(content) LIKE $%d ESCAPE '\\'", len(messageArgs))
+		}
+
+		rows, err = tx.QueryContext(ctx, `
+			SELECT conversation_id, id, role, content FROM messages
+			WHERE conversation_id IN (`+strings.Join(placeholders, ", ")+`) AND role IN ('user', 'assistant') AND (`+strings.Join(matches, " OR ")+`)
+			ORDER BY conversation_id, position`, messageArgs...)
+
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		for rows.Next() {
+			var conversationID, id, role string
+			var m Message
+
+			if err := rows.Scan(&conversationID, &id, &role, &m.Content); err != nil {
+				return err
+			}
+
+			if m.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+				return err
+			}
+
+			m.Role = Role(role)
+			if c, ok := byID[conversationID]; ok {
+				c.Messages = append(c.Messages, &m)
+			}
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		items[i] = newSearchResult(item.Conversation, terms)
+	}
+
+	return items, nil
+}
+
+func (s *SQLStore) ListMessages(ctx context.Context, conversationID string, q MessageQuery) ([]*Message, string, error) {
+	after, err := decodeCursor(q.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return nil, "", twirp.NotFoundError("invalid conversation ID")
+	}
+
+	limit := q.limit()
+	var items []*Message
+
+	err = s.tx(ctx, func(tx *sql.Tx) error {
+		if err := checkAccess(ctx, tx, oid); err != nil {
+			return err
+		}
+
+		// One extra message is fetched to know whether there is a next page.
+		if after == nil {
+			items, err = selectMessages(ctx, tx, "conversation_id = $1", "created_at, id LIMIT $2", oid.Hex(), limit+1)
+		} else {
+			items, err = selectMessages(ctx, tx,
+				"conversation_id = $1 AND (created_at > $2 OR (created_at = $2 AND id > $3))", "created_at, id LIMIT $4",
+				oid.Hex(), after.CreatedAt.UnixNano(), after.ID.Hex(), limit+1)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(items) <= limit {
+		return items, "", nil
+	}
+
+	items = items[:limit]
+	return items, encodeCursor(items[limit-1].CreatedAt, items[limit-1].ID), nil
+}
+
+func (s *SQLStore) PinMessage(ctx context.Context, conversationID, messageID string, pinned bool) error {
+	cid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	mid, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return twirp.NotFoundError("invalid message ID")
+	}
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if err := checkAccess(ctx, tx, cid); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `UPDATE messages SET pinned = $1 WHERE id = $2 AND conversation_id = $3`,
+			pinned, mid.Hex(), cid.Hex())
+		if err != nil {
+			return err
+		}
+
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return twirp.NotFoundError("message not found")
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLStore) SetSummary(ctx context.Context, conversationID string, summary *Summary) error {
+	oid, err := primitive.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return twirp.NotFoundError("invalid conversation ID")
+	}
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if err := checkAccess(ctx, tx, oid); err != nil {
+			return err
+		}
+
+		return setSummary(ctx, tx, oid, summary)
+	})
+}
+
+func setSummary(ctx context.Context, tx *sql.Tx, conversationID primitive.ObjectID, summary *Summary) error {
+	usage := cmp.Or(summary.Usage, &Usage{})
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE conversations SET summary = $1, summary_through = $2, summary_updated_at = $3, `+addUsage(4)+` WHERE id = $8`,
+		summary.Text, summary.Through.Hex(), summary.UpdatedAt.UnixNano(),
+		usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, usage.Cost, conversationID.Hex())
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	return nil
+}
+
+// checkAccess returns the error accessing the conversation fails with in the scope of the context, if any.
+func checkAccess(ctx context.Context, tx *sql.Tx, id primitive.ObjectID) error {
+	var c Conversation
+
+	err := tx.QueryRowContext(ctx, `SELECT owner_id, tenant_id FROM conversations WHERE id = $1`, id.Hex()).Scan(&c.OwnerID, &c.TenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return authorize(ctx, &c)
+}
+
+// tx runs fn in a transaction, committing it when fn succeeds, and then calls the optional onCommit functions.
+func (s *SQLStore) tx(ctx context.Context, fn func(tx *sql.Tx) error, onCommit ...func()) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, f := range onCommit {
+		f()
+	}
+
+	return nil
+}
+
+// updateError tells apart a deleted conversation from a concurrently updated one, when a versioned update of c
+// affected no row.
+func updateError(ctx context.Context, tx *sql.Tx, res sql.Result, c *Conversation) error {
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	var n int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM conversations WHERE id = $1`, c.ID.Hex()).Scan(&n); err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	return errConflict
+}
+
+// conversationColumns are the columns scanConversation reads, in order.
+const conversationColumns = "id, title, created_at, updated_at, version, summary, summary_through, summary_updated_at, " +
+	"prompt_tokens, completion_tokens, total_tokens, cost, owner_id, tenant_id"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanConversation(row scanner) (*Conversation, error) {
+	var id, title, summary, summaryThrough, owner, tenant string
+	var createdAt, updatedAt, version, summaryUpdatedAt int64
+	var usage Usage
+
+	if err := row.Scan(&id, &title, &createdAt, &updatedAt, &version, &summary, &summaryThrough, &summaryUpdatedAt,
+		&usage.PromptTokens, &usage.CompletionTokens, &usage.TotalTokens, &usage.Cost, &owner, &tenant); err != nil {
+		return nil, err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Conversation{
+		ID:        oid,
+		Title:     title,
+		CreatedAt: time.Unix(0, createdAt).UTC(),
+		UpdatedAt: time.Unix(0, updatedAt).UTC(),
+		Version:   version,
+		Usage:     usage,
+		OwnerID:   owner,
+		TenantID:  tenant,
+	}
+
+	if summary != "" {
+		through, err := primitive.ObjectIDFromHex(summaryThrough)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Summary = &Summary{Text: summary, Through: through, UpdatedAt: time.Unix(0, summaryUpdatedAt).UTC()}
+	}
+
+	return c, nil
+}
+
+// insertMessages stores messages, numbering them from the given position.
+func insertMessages(ctx context.Context, tx *sql.Tx, conversationID primitive.ObjectID, position int, messages []*Message) error {
+	for i, m := range messages {
+		usage := cmp.Or(m.Usage, &Usage{})
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO messages (id, conversation_id, position, role, content, tool_call_id, tool_name, pinned, created_at, updated_at,
+				model, prompt_tokens, completion_tokens, total_tokens, cost)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+			m.ID.Hex(), conversationID.Hex(), position+i, string(m.Role), m.Content, m.ToolCallID, m.ToolName, m.Pinned,
+			m.CreatedAt.UnixNano(), m.UpdatedAt.UnixNano(),
+			m.Model, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, usage.Cost); err != nil {
+			return err
+		}
+
+		for j, call := range m.ToolCalls {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO tool_calls (message_id, position, id, name, arguments) VALUES ($1, $2, $3, $4, $5)`,
+				m.ID.Hex(), j, call.ID, call.Name, call.Arguments); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectMessages returns the messages matching the where clause, in the given order, with their tool calls.
+func selectMessages(ctx context.Context, tx *sql.Tx, where, order string, args ...any) ([]*Message, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, role, content, tool_call_id, tool_name, pinned, created_at, updated_at,
+			model, prompt_tokens, completion_tokens, total_tokens, cost
+		FROM messages WHERE `+where+` ORDER BY `+order,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*Message
+	var ids []any
+	byID := map[string]*Message{}
+
+	for rows.Next() {
+		var m Message
+		var id, role string
+		var createdAt, updatedAt int64
+		var usage Usage
+
+		if err := rows.Scan(&id, &role, &m.Content, &m.ToolCallID, &m.ToolName, &m.Pinned, &createdAt, &updatedAt,
+			&m.Model, &usage.PromptTokens, &usage.CompletionTokens, &usage.TotalTokens, &usage.Cost); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		if m.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		m.Role = Role(role)
+		m.CreatedAt = time.Unix(0, createdAt).UTC()
+		m.UpdatedAt = time.Unix(0, updatedAt).UTC()
+
+		// Only assistant messages have a usage, the others are stored with zero columns.
+		if usage != (Usage{}) {
+			m.Usage = &usage
+		}
+
+		messages = append(messages, &m)
+		ids = append(ids, id)
+		byID[id] = &m
+	}
+
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return messages, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	for i := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	rows, err = tx.QueryContext(ctx,
+		`SELECT message_id, id, name, arguments FROM tool_calls
+		WHERE message_id IN (`+strings.Join(placeholders, ", ")+`) ORDER BY message_id, position`, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var messageID string
+		var call ToolCall
+
+		if err := rows.Scan(&messageID, &call.ID, &call.Name, &call.Arguments); err != nil {
+			return nil, err
+		}
+
+		if m, ok := byID[messageID]; ok {
+			m.ToolCalls = append(m.ToolCalls, &call)
+		}
+	}
+
+	return messages, rows.Err()
+}
+
+func deleteMessages(ctx context.Context, tx *sql.Tx, conversationID primitive.ObjectID) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM tool_calls WHERE message_id IN (SELECT id FROM messages WHERE conversation_id = $1)`,
+		conversationID.Hex()); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE conversation_id = $1`, conversationID.Hex())
+	return err
+}
+
+// escapeLike escapes the LIKE wildcards in s, using backslash as the escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"context"
+
+	"github.com/twitchtv/twirp"
+)
+
+// ConversationStore persists conversations. Implementations return twirp.NotFound errors for unknown or malformed
+// conversation IDs, and twirp.InvalidArgument errors for malformed page tokens.
+//
+// Stores are scoped to the owner of the context, see WithOwner.
+//
+// Updates are optimistic: they only apply when the stored conversation is still at c.Version, which they increment,
+// and return a twirp.Aborted error when another update got there first.
+type ConversationStore interface {
+	CreateConversation(ctx context.Context, c *Conversation) error
+	DescribeConversation(ctx context.Context, id string) (*Conversation, error)
+	ListConversations(ctx context.Context, q ListQuery) ([]*Conversation, string, error)
+	UpdateConversation(ctx context.Context, c *Conversation) error
+
+	// AppendMessages adds messages to the end of the stored conversation, sets its update time to c.UpdatedAt and adds
+	// the usage of the messages to its totals, and to c.Usage. c.Messages is left as is, callers usually have appended
+	// the messages to it already.
+	AppendMessages(ctx context.Context, c *Conversation, messages ...*Message) error
+
+	DeleteConversation(ctx context.Context, id string) error
+	SearchConversations(ctx context.Context, query string, limit int) ([]*SearchResult, error)
+
+	// ListMessages returns a page of the messages of a conversation, oldest first, along with the token of the next
+	// page, which is empty on the last page.
+	ListMessages(ctx context.Context, conversationID string, q MessageQuery) ([]*Message, string, error)
+
+	// PinMessage sets whether a message of a conversation is pinned. It does not change the conversation version.
+	PinMessage(ctx context.Context, conversationID, messageID string, pinned bool) error
+
+	// SetSummary stores the summary of a conversation and adds its usage to the conversation totals. It does not
+	// change the conversation version.
+	SetSummary(ctx context.Context, conversationID string, summary *Summary) error
+}
+
+var (
+	_ ConversationStore = (*Repository)(nil)
+	_ ConversationStore = (*MemoryStore)(nil)
+	_ ConversationStore = (*SQLStore)(nil)
+)
+
+// errConflict is returned when a conversation was updated since it was read. Clients may retry the request.
+var errConflict = twirp.NewError(twirp.Aborted, "conversation was updated concurrently, please retry")
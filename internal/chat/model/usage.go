@@ -0,0 +1,42 @@
+package model
+
+import "github.com/acai-travel/tech-challenge/internal/pb"
+
+// Usage is the number of tokens taken by completions, and their estimated cost in US dollars.
+type Usage struct {
+	PromptTokens     int64   `bson:"prompt_tokens"`
+	CompletionTokens int64   `bson:"completion_tokens"`
+	TotalTokens      int64   `bson:"total_tokens"`
+	Cost             float64 `bson:"cost"`
+}
+
+// Add adds o, which may be nil, to the usage.
+func (u *Usage) Add(o *Usage) {
+	if o == nil {
+		return
+	}
+
+	u.PromptTokens += o.PromptTokens
+	u.CompletionTokens += o.CompletionTokens
+	u.TotalTokens += o.TotalTokens
+	u.Cost += o.Cost
+}
+
+func (u *Usage) Proto() *pb.Usage {
+	return &pb.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+		EstimatedCost:    u.Cost,
+	}
+}
+
+// sumUsage returns the total usage of the messages.
+func sumUsage(messages []*Message) Usage {
+	var u Usage
+	for _, m := range messages {
+		u.Add(m.Usage)
+	}
+
+	return u
+}
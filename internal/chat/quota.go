@@ -0,0 +1,144 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
+	"github.com/twitchtv/twirp"
+)
+
+// QuotaBackend counts the tokens each caller used per UTC day. Backends shared between the instances of a deployment,
+// e.g. on Redis, enforce the quota across all of them.
+type QuotaBackend interface {
+	// Used returns the tokens the key used on the day, formatted as 2006-01-02.
+	Used(ctx context.Context, key, day string) (int64, error)
+
+	// Add counts tokens used by the key on the day.
+	Add(ctx context.Context, key, day string, tokens int64) error
+}
+
+// MemoryQuotas is a QuotaBackend local to the process. Only the counts of the current day are kept.
+type MemoryQuotas struct {
+	mu   sync.Mutex
+	day  string
+	used map[string]int64
+}
+
+func NewMemoryQuotas() *MemoryQuotas {
+	return &MemoryQuotas{used: map[string]int64{}}
+}
+
+func (m *MemoryQuotas) Used(ctx context.Context, key, day string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if day != m.day {
+		return 0, nil
+	}
+
+	return m.used[key], nil
+}
+
+func (m *MemoryQuotas) Add(ctx context.Context, key, day string, tokens int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if day > m.day {
+		m.day = day
+		clear(m.used)
+	} else if day < m.day {
+		return nil
+	}
+
+	m.used[key] += tokens
+	return nil
+}
+
+// Quota limits the LLM tokens each caller may use per UTC day, as counted by the usage of the messages, titles and
+// summaries generated for them.
+type Quota struct {
+	// Daily is the number of tokens a caller may use per day, 0 for unlimited. Tenants may override it.
+	Daily int64
+
+	backend QuotaBackend
+	now     func() time.Time
+}
+
+func NewQuota(daily int64, backend QuotaBackend) *Quota {
+	return &Quota{Daily: daily, backend: backend, now: time.Now}
+}
+
+// QuotaFromEnv reads the daily token quota from DAILY_TOKEN_QUOTA, and counts the usage in memory. The quota is
+// unlimited when the variable is not set, the quotas of the tenants still being enforced.
+func QuotaFromEnv() *Quota {
+	var daily int64
+	if v := os.Getenv("DAILY_TOKEN_QUOTA"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			slog.Warn("Ignoring invalid DAILY_TOKEN_QUOTA", "value", v)
+		} else {
+			daily = parsed
+		}
+	}
+
+	return NewQuota(daily, NewMemoryQuotas())
+}
+
+// Check fails with a twirp.ResourceExhausted error when the caller of ctx, see authorize, has used their tokens of the
+// day. Its retry_after metadata gives the number of seconds until the quota is reset. Callers are let through when the
+// backend fails.
+func (q *Quota) Check(ctx context.Context) error {
+	if q == nil {
+		return nil
+	}
+
+	daily := q.Daily
+	if t := tenant.FromContext(ctx); t != nil && t.DailyTokenQuota != nil {
+		daily = *t.DailyTokenQuota
+	}
+
+	if daily <= 0 {
+		return nil
+	}
+
+	now := q.now().UTC()
+	used, err := q.backend.Used(ctx, quotaKey(ctx), now.Format(time.DateOnly))
+	if err != nil {
+		slog.ErrorContext(ctx, "Quota backend failed", "error", err)
+		return nil
+	}
+
+	if used < daily {
+		return nil
+	}
+
+	reset := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	retryAfter := strconv.Itoa(int(math.Ceil(reset.Sub(now).Seconds())))
+
+	slog.InfoContext(ctx, "Daily token quota exhausted", "owner_id", model.OwnerFrom(ctx), "tenant_id", model.TenantFrom(ctx), "used", used, "quota", daily)
+	return twirp.NewError(twirp.ResourceExhausted, "daily token quota exhausted, please retry tomorrow").
+		WithMeta("retry_after", retryAfter)
+}
+
+// Record counts the tokens of the usage against the quota of the caller of ctx.
+func (q *Quota) Record(ctx context.Context, usage *model.Usage) {
+	if q == nil || usage == nil || usage.TotalTokens == 0 {
+		return
+	}
+
+	if err := q.backend.Add(ctx, quotaKey(ctx), q.now().UTC().Format(time.DateOnly), usage.TotalTokens); err != nil {
+		slog.ErrorContext(ctx, "Failed to record token usage", "error", err)
+	}
+}
+
+// quotaKey identifies the caller of ctx, the same user has a quota per tenant.
+func quotaKey(ctx context.Context) string {
+	return model.TenantFrom(ctx) + "/" + model.OwnerFrom(ctx)
+}
@@ -2,13 +2,19 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"go.opentelemetry.io/otel"
 	"log/slog"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/acai-travel/tech-challenge/internal/chat/assistant"
 	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/httpx"
 	"github.com/acai-travel/tech-challenge/internal/pb"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
 	"github.com/twitchtv/twirp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,33 +22,94 @@ import (
 var _ pb.ChatService = (*Server)(nil)
 
 type Assistant interface {
-	Title(ctx context.Context, conv *model.Conversation) (string, error)
-	Reply(ctx context.Context, conv *model.Conversation) (string, error)
+	Title(ctx context.Context, conv *model.Conversation) (string, *model.Usage, error)
+	Reply(ctx context.Context, conv *model.Conversation) ([]*model.Message, error)
+	ReplyStream(ctx context.Context, conv *model.Conversation, emit func(assistant.Event)) ([]*model.Message, error)
+	Summarize(ctx context.Context, conv *model.Conversation) (*model.Summary, error)
 }
 
+// summaryTimeout bounds the background generation of a conversation summary.
+const summaryTimeout = 2 * time.Minute
+
 type Server struct {
-	repo   *model.Repository
+	repo   model.ConversationStore
 	assist Assistant
+	quota  *Quota
+
+	// summarizing holds the IDs of the conversations being summarized in the background.
+	summarizing sync.Map
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithQuota limits the tokens each caller may use per day.
+func WithQuota(q *Quota) Option {
+	return func(s *Server) { s.quota = q }
 }
 
-func NewServer(repo *model.Repository, assist Assistant) *Server {
-	return &Server{repo: repo, assist: assist}
+func NewServer(repo model.ConversationStore, assist Assistant, opts ...Option) *Server {
+	s := &Server{repo: repo, assist: assist}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 type titleRequest struct {
 	Title string
+	Usage *model.Usage
 	Err   error
 }
 
 var tracer = otel.Tracer("chat-server")
 
+// authorize scopes the store to the caller authenticated by httpx.Auth and to the tenant of the request, so that
+// users only access their own conversations.
+func authorize(ctx context.Context) (context.Context, error) {
+	p, ok := httpx.PrincipalFrom(ctx)
+	if !ok {
+		return nil, twirp.Unauthenticated.Error("missing credentials")
+	}
+
+	ctx = model.WithOwner(ctx, p.Subject)
+	if t := tenant.FromContext(ctx); t != nil {
+		ctx = model.WithTenant(ctx, t.ID)
+	}
+
+	return ctx, nil
+}
+
 func (s *Server) StartConversation(ctx context.Context, req *pb.StartConversationRequest) (*pb.StartConversationResponse, error) {
 	ctx, span := tracer.Start(ctx, "StartConversation")
 	defer span.End()
 
+	conversation, reply, err := s.startConversation(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StartConversationResponse{
+		ConversationId: conversation.ID.Hex(),
+		Title:          conversation.Title,
+		Reply:          reply.Content,
+	}, nil
+}
+
+// startConversation creates a conversation from the request and generates its title and first reply. When emit is
+// not nil the reply is streamed and progress is reported to it.
+func (s *Server) startConversation(ctx context.Context, req *pb.StartConversationRequest, emit func(assistant.Event)) (*model.Conversation, *model.Message, error) {
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	epoch := time.Now()
 	conversation := &model.Conversation{
 		ID:        primitive.NewObjectID(),
+		OwnerID:   model.OwnerFrom(ctx),
+		TenantID:  model.TenantFrom(ctx),
 		Title:     "Untitled conversation",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -56,68 +123,95 @@ func (s *Server) StartConversation(ctx context.Context, req *pb.StartConversatio
 	}
 
 	if strings.TrimSpace(req.GetMessage()) == "" {
-		return nil, twirp.RequiredArgumentError("message")
+		return nil, nil, twirp.RequiredArgumentError("message")
+	}
+
+	if err := s.quota.Check(ctx); err != nil {
+		return nil, nil, err
 	}
 
 	titleChan := make(chan titleRequest, 1)
 
+	// The title is generated from a snapshot, as the reply is appended to the conversation concurrently.
+	snapshot := *conversation
+	snapshot.Messages = slices.Clone(conversation.Messages)
+
 	go func() {
-		title, err := s.assist.Title(ctx, conversation)
-		titleChan <- titleRequest{Title: title, Err: err}
+		title, usage, err := s.assist.Title(ctx, &snapshot)
+		s.quota.Record(ctx, usage)
+		titleChan <- titleRequest{Title: title, Usage: usage, Err: err}
 	}()
 
 	// generate a reply
-	reply, err := s.assist.Reply(ctx, conversation)
+	reply, err := s.reply(ctx, conversation, emit)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	conversation.Messages = append(conversation.Messages, &model.Message{
-		ID:        primitive.NewObjectID(),
-		Role:      model.RoleAssistant,
-		Content:   reply,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	})
-
 	// Wait for title generation to complete
 	titleResp := <-titleChan
 	if titleResp.Err != nil {
-		slog.ErrorContext(ctx, "Failed to generate conversation title", "error", err)
+		slog.ErrorContext(ctx, "Failed to generate conversation title", "error", titleResp.Err)
 	} else {
 		conversation.Title = titleResp.Title
 	}
 
+	// The title is paid for even when it could not be used.
+	conversation.Usage.Add(titleResp.Usage)
+	for _, m := range conversation.Messages {
+		conversation.Usage.Add(m.Usage)
+	}
+
 	if err := s.repo.CreateConversation(ctx, conversation); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	slog.InfoContext(ctx, "Successfully created conversation", "conversation_id", conversation.ID, "duration_ms", time.Since(epoch).Milliseconds())
+	s.summarize(ctx, conversation)
 
-	return &pb.StartConversationResponse{
-		ConversationId: conversation.ID.Hex(),
-		Title:          conversation.Title,
-		Reply:          reply,
-	}, nil
+	return conversation, reply, nil
 }
 
 func (s *Server) ContinueConversation(ctx context.Context, req *pb.ContinueConversationRequest) (*pb.ContinueConversationResponse, error) {
 	ctx, span := tracer.Start(ctx, "ContinueConversation")
 	defer span.End()
 
+	_, reply, err := s.continueConversation(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ContinueConversationResponse{Reply: reply.Content}, nil
+}
+
+// continueConversation appends the requested message to an existing conversation and generates a reply. When emit
+// is not nil the reply is streamed and progress is reported to it.
+func (s *Server) continueConversation(ctx context.Context, req *pb.ContinueConversationRequest, emit func(assistant.Event)) (*model.Conversation, *model.Message, error) {
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if req.GetConversationId() == "" {
-		return nil, twirp.RequiredArgumentError("conversation_id")
+		return nil, nil, twirp.RequiredArgumentError("conversation_id")
 	}
 
 	if strings.TrimSpace(req.GetMessage()) == "" {
-		return nil, twirp.RequiredArgumentError("message")
+		return nil, nil, twirp.RequiredArgumentError("message")
 	}
 
 	conversation, err := s.repo.DescribeConversation(ctx, req.GetConversationId())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if err := s.quota.Check(ctx); err != nil {
+		return nil, nil, err
 	}
 
+	// Only the messages of this turn are stored, so that concurrent turns conflict instead of overwriting each other.
+	turn := len(conversation.Messages)
+
 	conversation.UpdatedAt = time.Now()
 	conversation.Messages = append(conversation.Messages, &model.Message{
 		ID:        primitive.NewObjectID(),
@@ -127,38 +221,122 @@ func (s *Server) ContinueConversation(ctx context.Context, req *pb.ContinueConve
 		UpdatedAt: time.Now(),
 	})
 
-	reply, err := s.assist.Reply(ctx, conversation)
+	reply, err := s.reply(ctx, conversation, emit)
 	if err != nil {
-		return nil, twirp.InternalErrorWith(err)
+		return nil, nil, twirp.InternalErrorWith(err)
 	}
 
-	conversation.Messages = append(conversation.Messages, &model.Message{
-		ID:        primitive.NewObjectID(),
-		Role:      model.RoleAssistant,
-		Content:   reply,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	})
+	if err := s.repo.AppendMessages(ctx, conversation, conversation.Messages[turn:]...); err != nil {
+		if _, ok := err.(twirp.Error); ok {
+			return nil, nil, err
+		}
+		return nil, nil, twirp.InternalErrorWith(err)
+	}
 
-	if err := s.repo.UpdateConversation(ctx, conversation); err != nil {
-		return nil, twirp.InternalErrorWith(err)
+	s.summarize(ctx, conversation)
+	return conversation, reply, nil
+}
+
+// summarize updates the summary of the conversation in the background, when the assistant finds it has grown enough
+// since the last one. At most one summary of a conversation is generated at a time.
+func (s *Server) summarize(ctx context.Context, conversation *model.Conversation) {
+	if _, busy := s.summarizing.LoadOrStore(conversation.ID, struct{}{}); busy {
+		return
 	}
 
-	return &pb.ContinueConversationResponse{Reply: reply}, nil
+	// The summary outlives the request, the conversation must not be modified by the caller anymore.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), summaryTimeout)
+
+	go func() {
+		defer cancel()
+		defer s.summarizing.Delete(conversation.ID)
+
+		summary, err := s.assist.Summarize(ctx, conversation)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to summarize conversation", "conversation_id", conversation.ID, "error", err)
+			return
+		}
+
+		if summary == nil {
+			return
+		}
+
+		s.quota.Record(ctx, summary.Usage)
+
+		if err := s.repo.SetSummary(ctx, conversation.ID.Hex(), summary); err != nil {
+			slog.ErrorContext(ctx, "Failed to store conversation summary", "conversation_id", conversation.ID, "error", err)
+			return
+		}
+
+		slog.InfoContext(ctx, "Updated conversation summary", "conversation_id", conversation.ID)
+	}()
+}
+
+// reply generates the assistant reply, streaming it when emit is set, and appends it to the conversation along with
+// the tool calls and results that led to it. The final assistant message is returned.
+func (s *Server) reply(ctx context.Context, conversation *model.Conversation, emit func(assistant.Event)) (*model.Message, error) {
+	var messages []*model.Message
+	var err error
+
+	if emit != nil {
+		messages, err = s.assist.ReplyStream(ctx, conversation, emit)
+	} else {
+		messages, err = s.assist.Reply(ctx, conversation)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range messages {
+		s.quota.Record(ctx, m.Usage)
+	}
+
+	if len(messages) == 0 {
+		return nil, errors.New("assistant returned no reply")
+	}
+
+	conversation.Messages = append(conversation.Messages, messages...)
+	return messages[len(messages)-1], nil
 }
 
 func (s *Server) ListConversations(ctx context.Context, req *pb.ListConversationsRequest) (*pb.ListConversationsResponse, error) {
 	ctx, span := tracer.Start(ctx, "ListConversations")
 	defer span.End()
 
-	conversations, err := s.repo.ListConversations(ctx)
+	ctx, err := authorize(ctx)
 	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPageSize() < 0 {
+		return nil, twirp.InvalidArgumentError("page_size", "must not be negative")
+	}
+
+	query := model.ListQuery{
+		PageSize:      int(req.GetPageSize()),
+		PageToken:     req.GetPageToken(),
+		TitleContains: req.GetTitleContains(),
+	}
+
+	if req.GetCreatedAfter() != nil {
+		query.CreatedAfter = req.GetCreatedAfter().AsTime()
+	}
+
+	if req.GetCreatedBefore() != nil {
+		query.CreatedBefore = req.GetCreatedBefore().AsTime()
+	}
+
+	conversations, next, err := s.repo.ListConversations(ctx, query)
+	if err != nil {
+		if _, ok := err.(twirp.Error); ok {
+			return nil, err
+		}
 		return nil, twirp.InternalErrorWith(err)
 	}
 
-	resp := &pb.ListConversationsResponse{}
+	resp := &pb.ListConversationsResponse{NextPageToken: next}
 	for _, conv := range conversations {
-		conv.Messages = nil // Clear messages to avoid sending large data
 		resp.Conversations = append(resp.Conversations, conv.Proto())
 	}
 
@@ -169,6 +347,11 @@ func (s *Server) DescribeConversation(ctx context.Context, req *pb.DescribeConve
 	ctx, span := tracer.Start(ctx, "DescribeConversation")
 	defer span.End()
 
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.GetConversationId() == "" {
 		return nil, twirp.RequiredArgumentError("conversation_id")
 	}
@@ -184,3 +367,126 @@ func (s *Server) DescribeConversation(ctx context.Context, req *pb.DescribeConve
 
 	return &pb.DescribeConversationResponse{Conversation: conversation.Proto()}, nil
 }
+
+func (s *Server) DeleteConversation(ctx context.Context, req *pb.DeleteConversationRequest) (*pb.DeleteConversationResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeleteConversation")
+	defer span.End()
+
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == "" {
+		return nil, twirp.RequiredArgumentError("conversation_id")
+	}
+
+	if err := s.repo.DeleteConversation(ctx, req.GetConversationId()); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteConversationResponse{}, nil
+}
+
+func (s *Server) SearchConversations(ctx context.Context, req *pb.SearchConversationsRequest) (*pb.SearchConversationsResponse, error) {
+	ctx, span := tracer.Start(ctx, "SearchConversations")
+	defer span.End()
+
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return nil, twirp.RequiredArgumentError("query")
+	}
+
+	if req.GetLimit() < 0 {
+		return nil, twirp.InvalidArgumentError("limit", "must not be negative")
+	}
+
+	results, err := s.repo.SearchConversations(ctx, req.GetQuery(), int(req.GetLimit()))
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	resp := &pb.SearchConversationsResponse{}
+	for _, res := range results {
+		out := &pb.SearchConversationsResponse_Result{
+			Conversation: res.Conversation.Proto(),
+			Snippets:     res.Snippets,
+		}
+
+		for _, id := range res.MessageIDs {
+			out.MessageIds = append(out.MessageIds, id.Hex())
+		}
+
+		resp.Results = append(resp.Results, out)
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ListMessages(ctx context.Context, req *pb.ListMessagesRequest) (*pb.ListMessagesResponse, error) {
+	ctx, span := tracer.Start(ctx, "ListMessages")
+	defer span.End()
+
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == "" {
+		return nil, twirp.RequiredArgumentError("conversation_id")
+	}
+
+	if req.GetPageSize() < 0 {
+		return nil, twirp.InvalidArgumentError("page_size", "must not be negative")
+	}
+
+	messages, next, err := s.repo.ListMessages(ctx, req.GetConversationId(), model.MessageQuery{
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+	})
+
+	if err != nil {
+		if _, ok := err.(twirp.Error); ok {
+			return nil, err
+		}
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	resp := &pb.ListMessagesResponse{NextPageToken: next}
+	for _, m := range messages {
+		resp.Messages = append(resp.Messages, m.Proto())
+	}
+
+	return resp, nil
+}
+
+func (s *Server) PinMessage(ctx context.Context, req *pb.PinMessageRequest) (*pb.PinMessageResponse, error) {
+	ctx, span := tracer.Start(ctx, "PinMessage")
+	defer span.End()
+
+	ctx, err := authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == "" {
+		return nil, twirp.RequiredArgumentError("conversation_id")
+	}
+
+	if req.GetMessageId() == "" {
+		return nil, twirp.RequiredArgumentError("message_id")
+	}
+
+	if err := s.repo.PinMessage(ctx, req.GetConversationId(), req.GetMessageId(), req.GetPinned()); err != nil {
+		if _, ok := err.(twirp.Error); ok {
+			return nil, err
+		}
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	return &pb.PinMessageResponse{}, nil
+}
@@ -2,42 +2,857 @@ package chat
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/acai-travel/tech-challenge/internal/chat/assistant"
 	"github.com/acai-travel/tech-challenge/internal/chat/model"
 	. "github.com/acai-travel/tech-challenge/internal/chat/testing"
+	"github.com/acai-travel/tech-challenge/internal/llm"
 	"github.com/acai-travel/tech-challenge/internal/pb"
+	"github.com/acai-travel/tech-challenge/internal/tenant"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
 	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestServer_DescribeConversation(t *testing.T) {
-	ctx := context.Background()
-	srv := NewServer(model.New(ConnectMongo()), nil)
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, nil)
 
-	t.Run("describe existing conversation", WithFixture(func(t *testing.T, f *Fixture) {
-		c := f.CreateConversation()
+		t.Run("describe existing conversation", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
 
-		out, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()})
+			out, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, want := out.GetConversation(), c.Proto()
+			if !cmp.Equal(got, want, protocmp.Transform()) {
+				t.Errorf("DescribeConversation() mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
+			}
+		}))
+
+		t.Run("describe non existing conversation should return 404", WithFixture(store, func(t *testing.T, f *Fixture) {
+			_, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: "08a59244257c872c5943e2a2"})
+			if err == nil {
+				t.Fatal("expected error for non-existing conversation, got nil")
+			}
+
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+				t.Fatalf("expected twirp.NotFound error, got %v", err)
+			}
+		}))
+	})
+}
+
+func TestServer_DeleteConversation(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, nil)
+
+		t.Run("delete existing conversation", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+
+			if _, err := srv.DeleteConversation(ctx, &pb.DeleteConversationRequest{ConversationId: c.ID.Hex()}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+				t.Fatalf("expected twirp.NotFound error after delete, got %v", err)
+			}
+		}))
+
+		t.Run("delete non existing conversation should return 404", WithFixture(store, func(t *testing.T, f *Fixture) {
+			_, err := srv.DeleteConversation(ctx, &pb.DeleteConversationRequest{ConversationId: "08a59244257c872c5943e2a2"})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+				t.Fatalf("expected twirp.NotFound error, got %v", err)
+			}
+		}))
+	})
+}
+
+func TestServer_ListConversations(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, nil)
+
+		t.Run("list pages through matching conversations without messages", WithFixture(store, func(t *testing.T, f *Fixture) {
+			tag := uuid.New().String()
+
+			var want []string
+			for i := 0; i < 3; i++ {
+				c := f.CreateConversation(func(c *model.Conversation) {
+					c.Title = fmt.Sprintf("Trip %d %s", i, tag)
+					c.CreatedAt = c.CreatedAt.Add(time.Duration(i) * time.Hour)
+				})
+				want = append([]string{c.ID.Hex()}, want...)
+			}
+
+			var got []string
+			req := &pb.ListConversationsRequest{PageSize: 2, TitleContains: strings.ToUpper(tag)}
+
+			for page := 0; ; page++ {
+				out, err := srv.ListConversations(ctx, req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				for _, c := range out.GetConversations() {
+					if len(c.GetMessages()) > 0 {
+						t.Errorf("expected conversation %s without messages", c.GetId())
+					}
+					got = append(got, c.GetId())
+				}
+
+				if out.GetNextPageToken() == "" {
+					break
+				}
+
+				if page > len(want) {
+					t.Fatal("pagination does not terminate")
+				}
+
+				req.PageToken = out.GetNextPageToken()
+			}
+
+			if !cmp.Equal(got, want) {
+				t.Errorf("ListConversations() mismatch (-got +want):\n%s", cmp.Diff(got, want))
+			}
+		}))
+
+		t.Run("invalid page token should return invalid argument", func(t *testing.T) {
+			_, err := srv.ListConversations(ctx, &pb.ListConversationsRequest{PageToken: "not a token"})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+				t.Fatalf("expected twirp.InvalidArgument error, got %v", err)
+			}
+		})
+	})
+}
+
+func TestServer_ListMessages(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, nil)
+
+		t.Run("list pages through messages oldest first", WithFixture(store, func(t *testing.T, f *Fixture) {
+			var want []string
+			c := f.CreateConversation(func(c *model.Conversation) {
+				for i := 1; i < 5; i++ {
+					c.Messages = append(c.Messages, &model.Message{
+						ID:        primitive.NewObjectID(),
+						Role:      model.RoleAssistant,
+						Content:   fmt.Sprintf("Reply %d", i),
+						CreatedAt: c.CreatedAt.Add(time.Duration(i) * time.Minute),
+						UpdatedAt: c.CreatedAt.Add(time.Duration(i) * time.Minute),
+					})
+				}
+
+				for _, m := range c.Messages {
+					want = append(want, m.ID.Hex())
+				}
+			})
+
+			var got []string
+			req := &pb.ListMessagesRequest{ConversationId: c.ID.Hex(), PageSize: 2}
+
+			for page := 0; ; page++ {
+				out, err := srv.ListMessages(ctx, req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				for _, m := range out.GetMessages() {
+					got = append(got, m.GetId())
+				}
+
+				if out.GetNextPageToken() == "" {
+					break
+				}
+
+				if page > len(want) {
+					t.Fatal("pagination does not terminate")
+				}
+
+				req.PageToken = out.GetNextPageToken()
+			}
+
+			if !cmp.Equal(got, want) {
+				t.Errorf("ListMessages() mismatch (-got +want):\n%s", cmp.Diff(got, want))
+			}
+		}))
+
+		t.Run("list messages of non existing conversation should return 404", func(t *testing.T) {
+			_, err := srv.ListMessages(ctx, &pb.ListMessagesRequest{ConversationId: "08a59244257c872c5943e2a2"})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+				t.Fatalf("expected twirp.NotFound error, got %v", err)
+			}
+		})
+	})
+}
+
+func TestServer_PinMessage(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, nil)
+
+		t.Run("pin message", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+
+			_, err := srv.PinMessage(ctx, &pb.PinMessageRequest{ConversationId: c.ID.Hex(), MessageId: c.Messages[0].ID.Hex(), Pinned: true})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := store.DescribeConversation(ctx, c.ID.Hex())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !got.Messages[0].Pinned {
+				t.Error("expected message to be pinned")
+			}
+		}))
+
+		t.Run("pin non existing message should return 404", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+
+			_, err := srv.PinMessage(ctx, &pb.PinMessageRequest{ConversationId: c.ID.Hex(), MessageId: "08a59244257c872c5943e2a2", Pinned: true})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+				t.Fatalf("expected twirp.NotFound error, got %v", err)
+			}
+		}))
+	})
+}
+
+func TestServer_SearchConversations(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, nil)
+
+		t.Run("search returns matching messages with snippets", WithFixture(store, func(t *testing.T, f *Fixture) {
+			tag := strings.ReplaceAll(uuid.New().String(), "-", "")
+
+			c := f.CreateConversation(func(c *model.Conversation) {
+				c.Messages = append(c.Messages, &model.Message{
+					ID:        primitive.NewObjectID(),
+					Role:      model.RoleAssistant,
+					Content:   "Expect fresh snow in " + tag + " this weekend.",
+					CreatedAt: c.CreatedAt,
+					UpdatedAt: c.UpdatedAt,
+				})
+			})
+
+			out, err := srv.SearchConversations(ctx, &pb.SearchConversationsRequest{Query: tag})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(out.GetResults()) != 1 {
+				t.Fatalf("expected a single result, got %d", len(out.GetResults()))
+			}
+
+			res := out.GetResults()[0]
+			if res.GetConversation().GetId() != c.ID.Hex() || len(res.GetConversation().GetMessages()) > 0 {
+				t.Errorf("expected conversation %s without messages, got %v", c.ID.Hex(), res.GetConversation())
+			}
+
+			want := []string{c.Messages[1].ID.Hex()}
+			if !cmp.Equal(res.GetMessageIds(), want) {
+				t.Errorf("message IDs mismatch (-got +want):\n%s", cmp.Diff(res.GetMessageIds(), want))
+			}
+
+			if len(res.GetSnippets()) != 1 || !strings.Contains(res.GetSnippets()[0], "**"+tag+"**") {
+				t.Errorf("expected highlighted snippet, got %v", res.GetSnippets())
+			}
+		}))
+
+		t.Run("search ranks conversations with their own matching messages", WithFixture(store, func(t *testing.T, f *Fixture) {
+			tag := strings.ReplaceAll(uuid.New().String(), "-", "")
+
+			message := func(c *model.Conversation, content string) *model.Message {
+				return &model.Message{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: content, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
+			}
+
+			titled := f.CreateConversation(func(c *model.Conversation) {
+				c.Title = "Trip to " + tag
+			})
+
+			answered := f.CreateConversation(func(c *model.Conversation) {
+				c.Messages = append(c.Messages, message(c, "Flights to "+tag+"?"), message(c, "Hotels in "+tag+"?"))
+			})
+
+			once := f.CreateConversation(func(c *model.Conversation) {
+				c.Messages = append(c.Messages, message(c, "Is "+tag+" far?"))
+			})
+
+			out, err := srv.SearchConversations(ctx, &pb.SearchConversationsRequest{Query: tag})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, res := range out.GetResults() {
+				got = append(got, res.GetConversation().GetId())
+			}
+
+			// Ties are broken by creation date, the fixtures sharing theirs.
+			if len(got) != 3 || got[0] != answered.ID.Hex() || !slices.Contains(got[1:], once.ID.Hex()) || !slices.Contains(got[1:], titled.ID.Hex()) {
+				t.Fatalf("expected %s first, then %s and %s, got %v", answered.ID.Hex(), once.ID.Hex(), titled.ID.Hex(), got)
+			}
+
+			want := map[string][]string{
+				answered.ID.Hex(): {answered.Messages[1].ID.Hex(), answered.Messages[2].ID.Hex()},
+				once.ID.Hex():     {once.Messages[1].ID.Hex()},
+				titled.ID.Hex():   nil,
+			}
+
+			for _, res := range out.GetResults() {
+				id := res.GetConversation().GetId()
+				if !cmp.Equal(res.GetMessageIds(), want[id], cmpopts.EquateEmpty()) || len(res.GetSnippets()) != len(want[id]) {
+					t.Errorf("conversation %s: expected messages %v, got %v with snippets %v", id, want[id], res.GetMessageIds(), res.GetSnippets())
+				}
+			}
+		}))
+
+		t.Run("search without query should return invalid argument", func(t *testing.T) {
+			_, err := srv.SearchConversations(ctx, &pb.SearchConversationsRequest{Query: " "})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+				t.Fatalf("expected twirp.InvalidArgument error, got %v", err)
+			}
+		})
+	})
+}
+
+func TestServer_StartConversation(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, assistant.New(llm.NewFake()))
+
+		t.Run("start conversation stores title and reply", func(t *testing.T) {
+			out, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "What is the weather like in Barcelona?"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				_ = store.DeleteConversation(ctx, out.GetConversationId())
+			}()
+
+			if out.GetTitle() == "" || out.GetTitle() == "Untitled conversation" {
+				t.Errorf("expected generated title, got %q", out.GetTitle())
+			}
+
+			if want := "You said: What is the weather like in Barcelona?"; out.GetReply() != want {
+				t.Errorf("StartConversation() reply = %q, want %q", out.GetReply(), want)
+			}
+
+			c, err := store.DescribeConversation(ctx, out.GetConversationId())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.Title != out.GetTitle() || len(c.Messages) != 2 || c.Messages[1].Role != model.RoleAssistant || c.Messages[1].Content != out.GetReply() {
+				t.Errorf("unexpected stored conversation: %+v", c)
+			}
+
+			// The totals include the title generation on top of the reply.
+			reply := c.Messages[1].Usage
+			if reply == nil || reply.TotalTokens == 0 || c.Messages[1].Model == "" {
+				t.Fatalf("expected reply with model and usage, got %+v", c.Messages[1])
+			}
+
+			if c.Usage.TotalTokens <= reply.TotalTokens || c.Usage.Cost <= reply.Cost {
+				t.Errorf("expected conversation usage above the reply's %+v, got %+v", *reply, c.Usage)
+			}
+		})
+
+		t.Run("start conversation without message should return invalid argument", func(t *testing.T) {
+			_, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: " "})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+				t.Fatalf("expected twirp.InvalidArgument error, got %v", err)
+			}
+		})
+	})
+}
+
+func TestServer_ContinueConversation(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		t.Run("continue conversation appends the turn", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+			srv := NewServer(store, assistant.New(llm.NewFake()))
+
+			out, err := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: c.ID.Hex(), Message: "And tomorrow?"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want := "You said: And tomorrow?"; out.GetReply() != want {
+				t.Errorf("ContinueConversation() reply = %q, want %q", out.GetReply(), want)
+			}
+
+			got, err := store.DescribeConversation(ctx, c.ID.Hex())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got.Messages) != 3 || got.Messages[0].ID != c.Messages[0].ID || got.Messages[2].Content != out.GetReply() {
+				t.Errorf("unexpected stored messages: %+v", got.Messages)
+			}
+
+			if got.Version != c.Version+1 {
+				t.Errorf("Version = %d, want %d", got.Version, c.Version+1)
+			}
+
+			if reply := got.Messages[2].Usage; reply == nil || got.Usage != *reply {
+				t.Errorf("expected conversation usage to add the reply's %+v, got %+v", reply, got.Usage)
+			}
+		}))
+
+		t.Run("concurrent turn should return aborted", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+			srv := NewServer(store, racingAssistant{Assistant: assistant.New(llm.NewFake()), store: store})
+
+			_, err := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: c.ID.Hex(), Message: "And tomorrow?"})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.Aborted {
+				t.Fatalf("expected twirp.Aborted error, got %v", err)
+			}
+
+			// The concurrent turn must have been kept.
+			got, err := store.DescribeConversation(ctx, c.ID.Hex())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got.Messages) != 2 || got.Messages[1].Content != "Concurrent message" {
+				t.Errorf("unexpected stored messages: %+v", got.Messages)
+			}
+		}))
+	})
+}
+
+// racingAssistant stores a message in the conversation while generating its reply, as a concurrent turn would.
+type racingAssistant struct {
+	*assistant.Assistant
+	store model.ConversationStore
+}
+
+func (a racingAssistant) Reply(ctx context.Context, conv *model.Conversation) ([]*model.Message, error) {
+	other, err := a.store.DescribeConversation(ctx, conv.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	other.UpdatedAt = time.Now()
+	if err := a.store.AppendMessages(ctx, other, &model.Message{
+		ID:        primitive.NewObjectID(),
+		Role:      model.RoleUser,
+		Content:   "Concurrent message",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return a.Assistant.Reply(ctx, conv)
+}
+
+func TestServer_ToolMessages(t *testing.T) {
+	ctx := AuthContext(Owner)
+
+	// toolTurn is the expected turn of a question answered with the weather tool.
+	toolTurn := func(question string) []*model.Message {
+		return []*model.Message{
+			{Role: model.RoleUser, Content: question},
+			{Role: model.RoleAssistant, ToolCalls: []*model.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Barcelona"}`}}},
+			{Role: model.RoleTool, Content: "sunny", ToolCallID: "call_1", ToolName: "get_weather"},
+			{Role: model.RoleAssistant, Content: "It is sunny."},
+		}
+	}
+
+	newServer := func(store model.ConversationStore) *Server {
+		replies := llm.NewFake(
+			llm.Response{Message: llm.Message{ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Barcelona"}`}}}},
+			llm.Response{Message: llm.Message{Content: "It is sunny."}},
+		)
+
+		provider := replyProvider{replies: replies, titles: llm.NewFake()}
+		return NewServer(store, assistant.New(provider, stubTool{name: "get_weather", result: "sunny"}))
+	}
+
+	// stored compares the messages on the fields set by the tool loop.
+	stored := cmp.Transformer("stored", func(m *model.Message) model.Message {
+		return model.Message{Role: m.Role, Content: m.Content, ToolCalls: m.ToolCalls, ToolCallID: m.ToolCallID, ToolName: m.ToolName}
+	})
+
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		t.Run("start conversation stores tool calls and results", func(t *testing.T) {
+			out, err := newServer(store).StartConversation(ctx, &pb.StartConversationRequest{Message: "Is it sunny in Barcelona?"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				_ = store.DeleteConversation(ctx, out.GetConversationId())
+			}()
+
+			c, err := store.DescribeConversation(ctx, out.GetConversationId())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want := toolTurn("Is it sunny in Barcelona?"); !cmp.Equal(c.Messages, want, stored) {
+				t.Errorf("stored messages mismatch (-got +want):\n%s", cmp.Diff(c.Messages, want, stored))
+			}
+		})
+
+		t.Run("continue conversation appends tool calls and results", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+
+			if _, err := newServer(store).ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: c.ID.Hex(), Message: "And now?"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := store.DescribeConversation(ctx, c.ID.Hex())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := append([]*model.Message{c.Messages[0]}, toolTurn("And now?")...)
+			if !cmp.Equal(got.Messages, want, stored) {
+				t.Errorf("stored messages mismatch (-got +want):\n%s", cmp.Diff(got.Messages, want, stored))
+			}
+		}))
+	})
+}
+
+// replyProvider answers the reply requests, which offer tools, with replies, and the others, like titles, with titles,
+// so that titles generated concurrently do not consume the scripted replies.
+type replyProvider struct {
+	replies, titles llm.Provider
+}
+
+func (p replyProvider) Complete(ctx context.Context, req llm.Request, onDelta func(string)) (*llm.Response, error) {
+	if len(req.Tools) > 0 {
+		return p.replies.Complete(ctx, req, onDelta)
+	}
+
+	return p.titles.Complete(ctx, req, onDelta)
+}
+
+func TestServer_Summary(t *testing.T) {
+	ctx := AuthContext(Owner)
+	t.Setenv("LLM_SUMMARY_THRESHOLD", "2")
+
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, assistant.New(llm.NewFake()))
+
+		t.Run("long conversations are summarized in the background", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation(func(c *model.Conversation) {
+				c.Messages = append(c.Messages,
+					&model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "Sunny.", CreatedAt: c.CreatedAt, UpdatedAt: c.CreatedAt},
+					&model.Message{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: "And tomorrow?", CreatedAt: c.CreatedAt, UpdatedAt: c.CreatedAt},
+					&model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "Rainy.", CreatedAt: c.CreatedAt, UpdatedAt: c.CreatedAt},
+				)
+			})
+
+			if _, err := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: c.ID.Hex(), Message: "Thanks!"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				out, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if out.GetConversation().GetSummary() != "" {
+					break
+				}
+
+				if time.Now().After(deadline) {
+					t.Fatal("conversation was not summarized")
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+		}))
+	})
+}
+
+func TestServer_Ownership(t *testing.T) {
+	ctx := AuthContext(Owner)
+	other := AuthContext("other-user")
+
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, assistant.New(llm.NewFake()))
+
+		t.Run("foreign conversations are denied", WithFixture(store, func(t *testing.T, f *Fixture) {
+			c := f.CreateConversation()
+			id := c.ID.Hex()
+
+			calls := map[string]func() error{
+				"describe": func() error {
+					_, err := srv.DescribeConversation(other, &pb.DescribeConversationRequest{ConversationId: id})
+					return err
+				},
+				"continue": func() error {
+					_, err := srv.ContinueConversation(other, &pb.ContinueConversationRequest{ConversationId: id, Message: "Hi"})
+					return err
+				},
+				"delete": func() error {
+					_, err := srv.DeleteConversation(other, &pb.DeleteConversationRequest{ConversationId: id})
+					return err
+				},
+				"list messages": func() error {
+					_, err := srv.ListMessages(other, &pb.ListMessagesRequest{ConversationId: id})
+					return err
+				},
+				"pin": func() error {
+					_, err := srv.PinMessage(other, &pb.PinMessageRequest{ConversationId: id, MessageId: c.Messages[0].ID.Hex(), Pinned: true})
+					return err
+				},
+			}
+
+			for name, call := range calls {
+				err := call()
+				if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.PermissionDenied {
+					t.Errorf("%s: expected twirp.PermissionDenied error, got %v", name, err)
+				}
+			}
+
+			// The conversation was left untouched.
+			got, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: id})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if msgs := got.GetConversation().GetMessages(); len(msgs) != 1 || msgs[0].GetPinned() {
+				t.Errorf("unexpected messages: %v", msgs)
+			}
+		}))
+
+		t.Run("lists and searches only return own conversations", WithFixture(store, func(t *testing.T, f *Fixture) {
+			tag := strings.ReplaceAll(uuid.New().String(), "-", "")
+			f.CreateConversation(func(c *model.Conversation) {
+				c.Title = "Mine " + tag
+			})
+			f.CreateConversation(func(c *model.Conversation) {
+				c.Title = "Theirs " + tag
+				c.OwnerID = "other-user"
+			})
+
+			list, err := srv.ListConversations(ctx, &pb.ListConversationsRequest{TitleContains: tag})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := list.GetConversations(); len(got) != 1 || got[0].GetTitle() != "Mine "+tag {
+				t.Errorf("ListConversations() = %v, want only the caller's conversation", got)
+			}
+
+			search, err := srv.SearchConversations(ctx, &pb.SearchConversationsRequest{Query: tag})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := search.GetResults(); len(got) != 1 || got[0].GetConversation().GetTitle() != "Mine "+tag {
+				t.Errorf("SearchConversations() = %v, want only the caller's conversation", got)
+			}
+		}))
+
+		t.Run("started conversations belong to the caller", func(t *testing.T) {
+			out, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				_ = store.DeleteConversation(ctx, out.GetConversationId())
+			}()
+
+			c, err := store.DescribeConversation(ctx, out.GetConversationId())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.OwnerID != Owner {
+				t.Errorf("OwnerID = %q, want %q", c.OwnerID, Owner)
+			}
+		})
+
+		t.Run("unauthenticated calls are rejected", func(t *testing.T) {
+			_, err := srv.ListConversations(context.Background(), &pb.ListConversationsRequest{})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.Unauthenticated {
+				t.Fatalf("expected twirp.Unauthenticated error, got %v", err)
+			}
+		})
+	})
+}
+
+func TestServer_Tenants(t *testing.T) {
+	acai := tenant.WithTenant(AuthContext(Owner), &tenant.Tenant{ID: "acai"})
+	sunny := tenant.WithTenant(AuthContext(Owner), &tenant.Tenant{ID: "sunny"})
+
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		srv := NewServer(store, assistant.New(llm.NewFake()))
+
+		t.Run("conversations of other tenants are not found", WithFixture(store, func(t *testing.T, f *Fixture) {
+			tag := strings.ReplaceAll(uuid.New().String(), "-", "")
+			c := f.CreateConversation(func(c *model.Conversation) {
+				c.Title = tag
+				c.TenantID = "acai"
+			})
+
+			if _, err := srv.DescribeConversation(acai, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err := srv.DescribeConversation(sunny, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+				t.Fatalf("expected twirp.NotFound error, got %v", err)
+			}
+
+			list, err := srv.ListConversations(sunny, &pb.ListConversationsRequest{TitleContains: tag})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(list.GetConversations()) != 0 {
+				t.Errorf("expected no conversation for another tenant, got %v", list.GetConversations())
+			}
+
+			search, err := srv.SearchConversations(sunny, &pb.SearchConversationsRequest{Query: tag})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(search.GetResults()) != 0 {
+				t.Errorf("expected no result for another tenant, got %v", search.GetResults())
+			}
+		}))
+
+		t.Run("started conversations belong to the tenant", func(t *testing.T) {
+			out, err := srv.StartConversation(sunny, &pb.StartConversationRequest{Message: "Hello"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				_ = store.DeleteConversation(context.Background(), out.GetConversationId())
+			}()
+
+			c, err := store.DescribeConversation(context.Background(), out.GetConversationId())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.TenantID != "sunny" {
+				t.Errorf("TenantID = %q, want %q", c.TenantID, "sunny")
+			}
+		})
+	})
+}
+
+func TestServer_Quota(t *testing.T) {
+	ctx := AuthContext(Owner)
+	ForEachStore(t, func(t *testing.T, store model.ConversationStore) {
+		// The first conversation uses more than a token, exhausting the quota.
+		srv := NewServer(store, assistant.New(llm.NewFake()), WithQuota(NewQuota(1, NewMemoryQuotas())))
+
+		out, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello"})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		got, want := out.GetConversation(), c.Proto()
-		if !cmp.Equal(got, want, protocmp.Transform()) {
-			t.Errorf("DescribeConversation() mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
-		}
-	}))
+		defer func() {
+			_ = store.DeleteConversation(ctx, out.GetConversationId())
+		}()
 
-	t.Run("describe non existing conversation should return 404", WithFixture(func(t *testing.T, f *Fixture) {
-		_, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: "08a59244257c872c5943e2a2"})
-		if err == nil {
-			t.Fatal("expected error for non-existing conversation, got nil")
-		}
+		exhausted := func(t *testing.T, err error) {
+			t.Helper()
+
+			te, ok := err.(twirp.Error)
+			if !ok || te.Code() != twirp.ResourceExhausted {
+				t.Fatalf("expected twirp.ResourceExhausted error, got %v", err)
+			}
 
-		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
-			t.Fatalf("expected twirp.NotFound error, got %v", err)
+			if retryAfter := te.Meta("retry_after"); retryAfter == "" || retryAfter == "0" {
+				t.Errorf("expected retry_after metadata, got %q", retryAfter)
+			}
 		}
-	}))
+
+		t.Run("exhausted quota rejects new conversations", func(t *testing.T) {
+			_, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello again"})
+			exhausted(t, err)
+		})
+
+		t.Run("exhausted quota rejects new messages", func(t *testing.T) {
+			_, err := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: out.GetConversationId(), Message: "Still there?"})
+			exhausted(t, err)
+
+			c, err := store.DescribeConversation(ctx, out.GetConversationId())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Messages) != 2 {
+				t.Errorf("expected the rejected message not to be stored, got %d messages", len(c.Messages))
+			}
+		})
+
+		t.Run("quotas are per user", func(t *testing.T) {
+			other := AuthContext("other-user")
+
+			out, err := srv.StartConversation(other, &pb.StartConversationRequest{Message: "Hello"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_ = store.DeleteConversation(other, out.GetConversationId())
+		})
+
+		t.Run("tenants override the quota", func(t *testing.T) {
+			unlimited := int64(0)
+			ctx := tenant.WithTenant(ctx, &tenant.Tenant{ID: "sunny", DailyTokenQuota: &unlimited})
+
+			for range 2 {
+				out, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello"})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				_ = store.DeleteConversation(context.Background(), out.GetConversationId())
+			}
+		})
+
+		t.Run("tenant quotas apply without a global quota", func(t *testing.T) {
+			t.Setenv("DAILY_TOKEN_QUOTA", "")
+
+			quota := int64(1)
+			ctx := tenant.WithTenant(ctx, &tenant.Tenant{ID: "sunny", DailyTokenQuota: &quota})
+			srv := NewServer(store, assistant.New(llm.NewFake()), WithQuota(QuotaFromEnv()))
+
+			out, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_ = store.DeleteConversation(ctx, out.GetConversationId())
+
+			_, err = srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello again"})
+			exhausted(t, err)
+		})
+	})
 }
@@ -0,0 +1,160 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/assistant"
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/pb"
+	"github.com/twitchtv/twirp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamPathPrefix is the path prefix under which StreamHandler serves its endpoints.
+const StreamPathPrefix = "/stream/"
+
+const (
+	// eventPersisted is the final event of a successful stream, sent once the conversation has been stored.
+	eventPersisted = "message_persisted"
+	// eventError terminates a stream that failed after the first event has been sent.
+	eventError = "error"
+)
+
+// persistedEvent is the payload of the eventPersisted event.
+type persistedEvent struct {
+	ConversationID string `json:"conversation_id"`
+	MessageID      string `json:"message_id"`
+	Title          string `json:"title"`
+	Reply          string `json:"reply"`
+}
+
+// errorEvent is the payload of the eventError event, mirroring the Twirp error JSON.
+type errorEvent struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// StreamHandler returns an HTTP handler serving streaming variants of StartConversation and ContinueConversation as
+// server-sent events. The endpoints accept the same JSON request bodies as their Twirp counterparts:
+//
+//	POST /stream/StartConversation
+//	POST /stream/ContinueConversation
+//
+// While the reply is generated the stream emits "delta", "tool_call_started" and "tool_call_finished" events, and
+// finishes with a "message_persisted" event once the conversation has been stored, or an "error" event.
+func (s *Server) StreamHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+StreamPathPrefix+"StartConversation", func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "StreamStartConversation")
+		defer span.End()
+
+		req := &pb.StartConversationRequest{}
+		if err := decodeStreamRequest(r, req); err != nil {
+			_ = twirp.WriteError(w, err)
+			return
+		}
+
+		s.stream(ctx, w, func(emit func(assistant.Event)) (*model.Conversation, *model.Message, error) {
+			return s.startConversation(ctx, req, emit)
+		})
+	})
+
+	mux.HandleFunc("POST "+StreamPathPrefix+"ContinueConversation", func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "StreamContinueConversation")
+		defer span.End()
+
+		req := &pb.ContinueConversationRequest{}
+		if err := decodeStreamRequest(r, req); err != nil {
+			_ = twirp.WriteError(w, err)
+			return
+		}
+
+		s.stream(ctx, w, func(emit func(assistant.Event)) (*model.Conversation, *model.Message, error) {
+			return s.continueConversation(ctx, req, emit)
+		})
+	})
+
+	return mux
+}
+
+// stream runs fn while forwarding its events to the client. Errors raised before the first event are written as
+// regular Twirp errors, so validation failures keep their HTTP status codes.
+func (s *Server) stream(ctx context.Context, w http.ResponseWriter, fn func(emit func(assistant.Event)) (*model.Conversation, *model.Message, error)) {
+	sw := &sseWriter{w: w, rc: http.NewResponseController(w)}
+
+	conversation, reply, err := fn(func(e assistant.Event) {
+		if err := sw.send(string(e.Type), e); err != nil {
+			slog.WarnContext(ctx, "Failed to write stream event", "error", err)
+		}
+	})
+
+	if err != nil {
+		if !sw.started {
+			_ = twirp.WriteError(w, err)
+			return
+		}
+
+		terr, ok := err.(twirp.Error)
+		if !ok {
+			terr = twirp.InternalErrorWith(err)
+		}
+
+		_ = sw.send(eventError, errorEvent{Code: string(terr.Code()), Msg: terr.Msg()})
+		return
+	}
+
+	_ = sw.send(eventPersisted, persistedEvent{
+		ConversationID: conversation.ID.Hex(),
+		MessageID:      reply.ID.Hex(),
+		Title:          conversation.Title,
+		Reply:          reply.Content,
+	})
+}
+
+func decodeStreamRequest(r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return twirp.InternalErrorWith(err)
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, msg); err != nil {
+		return twirp.NewError(twirp.Malformed, "the json request could not be decoded: "+err.Error())
+	}
+
+	return nil
+}
+
+// sseWriter writes server-sent events, sending the stream headers along with the first event.
+type sseWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+}
+
+func (sw *sseWriter) send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if !sw.started {
+		sw.w.Header().Set("Content-Type", "text/event-stream")
+		sw.w.Header().Set("Cache-Control", "no-cache")
+		sw.w.Header().Set("Connection", "keep-alive")
+		sw.w.WriteHeader(http.StatusOK)
+		sw.started = true
+	}
+
+	if _, err := fmt.Fprintf(sw.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+
+	return sw.rc.Flush()
+}
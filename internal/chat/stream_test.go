@@ -0,0 +1,222 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/assistant"
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	. "github.com/acai-travel/tech-challenge/internal/chat/testing"
+	"github.com/acai-travel/tech-challenge/internal/llm"
+	"github.com/google/go-cmp/cmp"
+)
+
+// sseEvent is a server-sent event as read by a client.
+type sseEvent struct {
+	Event string
+	Data  string
+}
+
+// readEvents parses the server-sent events of body, failing the test on malformed framing.
+func readEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+
+	if !strings.HasSuffix(body, "\n\n") {
+		t.Fatalf("expected the stream to end with a blank line, got %q", body)
+	}
+
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSuffix(body, "\n\n"), "\n\n") {
+		lines := strings.Split(block, "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "event: ") || !strings.HasPrefix(lines[1], "data: ") {
+			t.Fatalf("malformed event %q", block)
+		}
+
+		events = append(events, sseEvent{Event: strings.TrimPrefix(lines[0], "event: "), Data: strings.TrimPrefix(lines[1], "data: ")})
+	}
+
+	return events
+}
+
+func streamRequest(ctx context.Context, srv *Server, method, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, StreamPathPrefix+method, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.StreamHandler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_StreamHandler(t *testing.T) {
+	ctx := AuthContext(Owner)
+
+	t.Run("start conversation streams deltas and persisted event", func(t *testing.T) {
+		store := model.NewMemoryStore()
+		srv := NewServer(store, assistant.New(llm.NewFake()))
+
+		rec := streamRequest(ctx, srv, "StartConversation", `{"message": "Is it sunny in Barcelona?"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body)
+		}
+
+		if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("Content-Type = %q, want text/event-stream", got)
+		}
+
+		if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+			t.Errorf("Cache-Control = %q, want no-cache", got)
+		}
+
+		if !rec.Flushed {
+			t.Error("expected the events to be flushed")
+		}
+
+		events := readEvents(t, rec.Body.String())
+
+		var deltas []string
+		for _, e := range events[:len(events)-1] {
+			if e.Event != string(assistant.EventDelta) {
+				t.Fatalf("expected only delta events before the last one, got %+v", events)
+			}
+
+			var delta assistant.Event
+			if err := json.Unmarshal([]byte(e.Data), &delta); err != nil {
+				t.Fatalf("invalid delta %q: %v", e.Data, err)
+			}
+			deltas = append(deltas, delta.Delta)
+		}
+
+		want := "You said: Is it sunny in Barcelona?"
+		if got := strings.Join(deltas, ""); got != want {
+			t.Errorf("deltas = %q, want %q", got, want)
+		}
+
+		last := events[len(events)-1]
+		if last.Event != eventPersisted {
+			t.Fatalf("expected a final %s event, got %+v", eventPersisted, last)
+		}
+
+		var persisted persistedEvent
+		if err := json.Unmarshal([]byte(last.Data), &persisted); err != nil {
+			t.Fatalf("invalid persisted event %q: %v", last.Data, err)
+		}
+
+		c, err := store.DescribeConversation(ctx, persisted.ConversationID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if persisted.Reply != want || persisted.Title != c.Title || len(c.Messages) != 2 || persisted.MessageID != c.Messages[1].ID.Hex() {
+			t.Errorf("persisted event %+v does not match the stored conversation %+v", persisted, c)
+		}
+	})
+
+	t.Run("continue conversation streams tool calls", WithFixture(model.NewMemoryStore(), func(t *testing.T, f *Fixture) {
+		c := f.CreateConversation()
+		fake := llm.NewFake(
+			llm.Response{Message: llm.Message{ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Barcelona"}`}}}},
+			llm.Response{Message: llm.Message{Content: "Sunny."}},
+		)
+		srv := NewServer(f.ConversationStore, assistant.New(fake, stubTool{name: "get_weather", result: "sunny"}))
+
+		rec := streamRequest(ctx, srv, "ContinueConversation", `{"conversation_id": "`+c.ID.Hex()+`", "message": "And tomorrow?"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body)
+		}
+
+		var got []string
+		for _, e := range readEvents(t, rec.Body.String()) {
+			got = append(got, e.Event)
+		}
+
+		want := []string{"tool_call_started", "tool_call_finished", "delta", eventPersisted}
+		if !cmp.Equal(got, want) {
+			t.Errorf("events mismatch (-got +want):\n%s", cmp.Diff(got, want))
+		}
+	}))
+
+	t.Run("failure after the first event is sent as an error event", func(t *testing.T) {
+		store := failingStore{ConversationStore: model.NewMemoryStore(), err: errors.New("disk full")}
+		srv := NewServer(store, assistant.New(llm.NewFake()))
+
+		rec := streamRequest(ctx, srv, "StartConversation", `{"message": "Hi"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 once streaming, got %d", rec.Code)
+		}
+
+		events := readEvents(t, rec.Body.String())
+		last := events[len(events)-1]
+		if last.Event != eventError {
+			t.Fatalf("expected a final %s event, got %+v", eventError, events)
+		}
+
+		var e errorEvent
+		if err := json.Unmarshal([]byte(last.Data), &e); err != nil || e.Code != "internal" {
+			t.Errorf("expected an internal error event, got %q: %v", last.Data, err)
+		}
+	})
+
+	t.Run("failures before the first event keep their status code", func(t *testing.T) {
+		srv := NewServer(model.NewMemoryStore(), assistant.New(llm.NewFake()))
+
+		tests := []struct {
+			name   string
+			ctx    context.Context
+			method string
+			body   string
+			status int
+			code   string
+		}{
+			{"malformed body", ctx, "StartConversation", `{`, http.StatusBadRequest, "malformed"},
+			{"missing message", ctx, "StartConversation", `{"message": " "}`, http.StatusBadRequest, "invalid_argument"},
+			{"unknown conversation", ctx, "ContinueConversation", `{"conversation_id": "08a59244257c872c5943e2a2", "message": "Hi"}`, http.StatusNotFound, "not_found"},
+			{"unauthenticated", context.Background(), "StartConversation", `{"message": "Hi"}`, http.StatusUnauthorized, "unauthenticated"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := streamRequest(tt.ctx, srv, tt.method, tt.body)
+				if rec.Code != tt.status {
+					t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+				}
+
+				if got := rec.Header().Get("Content-Type"); got != "application/json" {
+					t.Errorf("Content-Type = %q, want application/json", got)
+				}
+
+				var body errorEvent
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code != tt.code {
+					t.Errorf("expected twirp error %q, got %s", tt.code, rec.Body)
+				}
+			})
+		}
+	})
+}
+
+// stubTool answers every call with the same result.
+type stubTool struct {
+	name   string
+	result string
+}
+
+func (t stubTool) Name() string               { return t.name }
+func (t stubTool) Description() string        { return "Stub tool" }
+func (t stubTool) Parameters() map[string]any { return map[string]any{"type": "object"} }
+
+func (t stubTool) Execute(ctx context.Context, args ...string) (string, error) {
+	return t.result, nil
+}
+
+// failingStore fails to create conversations, once the reply has been generated.
+type failingStore struct {
+	model.ConversationStore
+	err error
+}
+
+func (s failingStore) CreateConversation(ctx context.Context, c *model.Conversation) error {
+	return s.err
+}
@@ -6,19 +6,70 @@ import (
 	"time"
 
 	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/acai-travel/tech-challenge/internal/httpx"
 	"github.com/google/uuid"
+	"github.com/twitchtv/twirp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ForEachStore runs test as a subtest against each conversation store: a fresh in-memory store, a fresh in-memory
+// SQLite database, MongoDB and PostgreSQL. The MongoDB subtest is skipped when the database is not reachable, see
+// SkipWithoutMongo, and the PostgreSQL one unless TEST_POSTGRES_URL is set. Both fail instead of skipping in CI.
+func ForEachStore(t *testing.T, test func(t *testing.T, store model.ConversationStore)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, model.NewMemoryStore())
+	})
+
+	t.Run("mongo", func(t *testing.T) {
+		SkipWithoutMongo(t)
+
+		test(t, model.New(ConnectMongo()))
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		store, closer, err := OpenSQLite()
+		if err != nil {
+			t.Fatalf("failed to open SQLite: %v", err)
+		}
+		defer closer()
+
+		test(t, store)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		store, err := ConnectPostgres()
+		if err != nil {
+			t.Fatalf("failed to connect to PostgreSQL: %v", err)
+		}
+
+		if store == nil {
+			if inCI() {
+				t.Fatal("TEST_POSTGRES_URL must be set in CI")
+			}
+			t.Skip("TEST_POSTGRES_URL is not set")
+		}
+
+		test(t, store)
+	})
+}
+
+// Owner is the user fixtures create conversations for.
+const Owner = "test-user"
+
+// AuthContext returns a context authenticated as the user, as httpx.Auth sets it.
+func AuthContext(user string) context.Context {
+	return httpx.WithPrincipal(context.Background(), &httpx.Principal{Subject: user})
+}
+
 type Fixture struct {
-	*model.Repository
+	model.ConversationStore
 	test   *testing.T
 	defers []func()
 }
 
-func WithFixture(runner func(t *testing.T, f *Fixture)) func(t *testing.T) {
+func WithFixture(store model.ConversationStore, runner func(t *testing.T, f *Fixture)) func(t *testing.T) {
 	return func(t *testing.T) {
-		f := &Fixture{Repository: model.New(ConnectMongo()), test: t}
+		f := &Fixture{ConversationStore: store, test: t}
 		defer f.Teardown()
 		runner(t, f)
 	}
@@ -27,6 +78,7 @@ func WithFixture(runner func(t *testing.T, f *Fixture)) func(t *testing.T) {
 func (f *Fixture) CreateConversation(mods ...func(*model.Conversation)) *model.Conversation {
 	c := &model.Conversation{
 		ID:        primitive.NewObjectID(),
+		OwnerID:   Owner,
 		Title:     uuid.New().String(),
 		CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
 		UpdatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
@@ -45,12 +97,18 @@ func (f *Fixture) CreateConversation(mods ...func(*model.Conversation)) *model.C
 
 	ctx := context.Background()
 
-	if err := f.Repository.CreateConversation(ctx, c); err != nil {
+	if err := f.ConversationStore.CreateConversation(ctx, c); err != nil {
 		f.test.Fatalf("failed to create conversation: %v", err)
 	}
 
 	f.defers = append(f.defers, func() {
-		if err := f.Repository.DeleteConversation(ctx, c.ID.Hex()); err != nil {
+		// Tests may have deleted the conversation already.
+		err := f.ConversationStore.DeleteConversation(ctx, c.ID.Hex())
+		if te, ok := err.(twirp.Error); ok && te.Code() == twirp.NotFound {
+			return
+		}
+
+		if err != nil {
 			f.test.Logf("failed to cleanup conversation %s: %v", c.ID.Hex(), err)
 		}
 	})
@@ -0,0 +1,59 @@
+package testing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/acai-travel/tech-challenge/internal/chat/model"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSQLite returns a store backed by a fresh in-memory SQLite database, and a function closing it.
+func OpenSQLite() (*model.SQLStore, func(), error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Every connection to a shared in-memory database sees the same data, a single one avoids locking errors.
+	db.SetMaxOpenConns(1)
+
+	store := model.NewSQLStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, nil, err
+	}
+
+	return store, func() { _ = db.Close() }, nil
+}
+
+var postgres *model.SQLStore
+var postgresErr error
+var postgresOnce sync.Once
+
+// ConnectPostgres returns a store backed by the PostgreSQL database at TEST_POSTGRES_URL, migrating it once. It returns
+// nil when the variable is not set.
+func ConnectPostgres() (*model.SQLStore, error) {
+	postgresOnce.Do(func() {
+		url := os.Getenv("TEST_POSTGRES_URL")
+		if url == "" {
+			return
+		}
+
+		db, err := sql.Open("postgres", url)
+		if err != nil {
+			postgresErr = err
+			return
+		}
+
+		postgres = model.NewSQLStore(db)
+		postgresErr = postgres.Migrate(context.Background())
+	})
+
+	return postgres, postgresErr
+}
@@ -0,0 +1,389 @@
+// Package holidays loads the ICS holiday calendars used by the assistant, and keeps them cached and up to date.
+package holidays
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+const (
+	// DefaultLink is the calendar used when none is configured.
+	DefaultLink = "https://www.officeholidays.com/ics/spain/catalonia"
+
+	// DefaultRefresh is how long calendars are used before being revalidated when no interval is configured.
+	DefaultRefresh = 24 * time.Hour
+
+	// DefaultTimeout bounds each calendar download when no timeout is configured.
+	DefaultTimeout = 10 * time.Second
+
+	// retryInterval delays the next download of a calendar after a failed one, stale events being served meanwhile.
+	retryInterval = 5 * time.Minute
+)
+
+// Config holds the settings of a Service.
+type Config struct {
+	// Link is the calendar of requests that do not name one. Defaults to DefaultLink.
+	Link string
+
+	// Refresh is how long a calendar is used before being revalidated. Defaults to DefaultRefresh.
+	Refresh time.Duration
+
+	// HTTPClient used to download calendars. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Timeout applied to each download on top of the caller's context. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// SeedFile is a local ICS file loaded as the calendar of Link, so that holidays are known without network access.
+	SeedFile string
+}
+
+// ConfigFromEnv reads the service configuration from HOLIDAY_CALENDAR_LINK, HOLIDAY_CALENDAR_REFRESH and
+// HOLIDAY_CALENDAR_FILE.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Link:     os.Getenv("HOLIDAY_CALENDAR_LINK"),
+		SeedFile: os.Getenv("HOLIDAY_CALENDAR_FILE"),
+	}
+
+	if v, err := time.ParseDuration(os.Getenv("HOLIDAY_CALENDAR_REFRESH")); err == nil {
+		cfg.Refresh = v
+	}
+
+	return cfg
+}
+
+// Service serves the events of holiday calendars. Calendars are downloaded once and revalidated with their ETag or
+// Last-Modified header after the refresh interval, the last events being served while the upstream is down. It is
+// safe for concurrent use.
+type Service struct {
+	link    string
+	refresh time.Duration
+	http    *http.Client
+	timeout time.Duration
+	now     func() time.Time
+
+	mu        sync.Mutex
+	calendars map[string]*calendar
+}
+
+// calendar is the cached state of a calendar. Its mutex is held while requests download it, so that concurrent
+// requests share a single download.
+type calendar struct {
+	mu           sync.Mutex
+	events       []*ics.VEvent
+	etag         string
+	lastModified string
+
+	// checked is when the calendar was last downloaded or revalidated, or failed to be and must be retried.
+	checked time.Time
+}
+
+// New returns a service with the given configuration, failing when the seed file cannot be loaded.
+func New(cfg Config) (*Service, error) {
+	s := &Service{
+		link:      cfg.Link,
+		refresh:   cfg.Refresh,
+		http:      cfg.HTTPClient,
+		timeout:   cfg.Timeout,
+		now:       time.Now,
+		calendars: map[string]*calendar{},
+	}
+
+	if s.link == "" {
+		s.link = DefaultLink
+	}
+
+	if s.refresh <= 0 {
+		s.refresh = DefaultRefresh
+	}
+
+	if s.http == nil {
+		s.http = http.DefaultClient
+	}
+
+	if s.timeout <= 0 {
+		s.timeout = DefaultTimeout
+	}
+
+	if cfg.SeedFile != "" {
+		f, err := os.Open(cfg.SeedFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		if err := s.Seed(s.link, f); err != nil {
+			return nil, fmt.Errorf("invalid holiday calendar %s: %w", cfg.SeedFile, err)
+		}
+	}
+
+	return s, nil
+}
+
+// Link returns the calendar of requests that do not name one.
+func (s *Service) Link() string {
+	return s.link
+}
+
+// Seed loads the ICS calendar read from r as the calendar at link. It is used until the refresh interval elapses, and
+// then as stale data while the link cannot be downloaded.
+func (s *Service) Seed(link string, r io.Reader) error {
+	cal, err := ics.ParseCalendar(r)
+	if err != nil {
+		return err
+	}
+
+	c := s.calendar(link)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = cal.Events()
+	c.etag, c.lastModified = "", ""
+	c.checked = s.now()
+
+	return nil
+}
+
+// Events returns the events of the calendar at link, or of the default calendar when link is empty.
+func (s *Service) Events(ctx context.Context, link string) ([]*ics.VEvent, error) {
+	if link == "" {
+		link = s.link
+	}
+
+	c := s.calendar(link)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded() && s.now().Sub(c.checked) < s.refresh {
+		return c.events, nil
+	}
+
+	if err := s.load(ctx, link, c); err != nil {
+		if !c.loaded() {
+			return nil, err
+		}
+
+		slog.WarnContext(ctx, "Failed to refresh holiday calendar, serving stale events", "link", link, "error", err)
+
+		// The download is retried sooner than a refresh, without failing every request meanwhile.
+		c.checked = s.now().Add(retryInterval - s.refresh)
+	}
+
+	return c.events, nil
+}
+
+// Run revalidates the calendars as they become due for a refresh until ctx is done, so that requests do not wait for
+// downloads. Calendars are downloaded without holding their lock, their events being served meanwhile.
+func (s *Service) Run(ctx context.Context) {
+	timer := time.NewTimer(s.untilDue())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		links := make([]string, 0, len(s.calendars))
+		for link := range s.calendars {
+			links = append(links, link)
+		}
+		s.mu.Unlock()
+
+		for _, link := range links {
+			s.revalidate(ctx, link)
+		}
+
+		timer.Reset(s.untilDue())
+	}
+}
+
+// untilDue returns how long until the first loaded calendar is due for a refresh, at most the refresh interval.
+// Calendars that never loaded are left to requests.
+func (s *Service) untilDue() time.Duration {
+	s.mu.Lock()
+	calendars := make([]*calendar, 0, len(s.calendars))
+	for _, c := range s.calendars {
+		calendars = append(calendars, c)
+	}
+	s.mu.Unlock()
+
+	now := s.now()
+	wait := s.refresh
+
+	for _, c := range calendars {
+		c.mu.Lock()
+		if c.loaded() {
+			wait = min(wait, c.checked.Add(s.refresh).Sub(now))
+		}
+		c.mu.Unlock()
+	}
+
+	return max(wait, 0)
+}
+
+// revalidate downloads the calendar at link when it is due for a refresh. The download is dropped when the calendar
+// was loaded or seeded meanwhile, as it is then older than the events served.
+func (s *Service) revalidate(ctx context.Context, link string) {
+	c := s.calendar(link)
+
+	c.mu.Lock()
+	fresh := c.loaded() && s.now().Sub(c.checked) < s.refresh
+	loaded, etag, lastModified, checked := c.loaded(), c.etag, c.lastModified, c.checked
+	c.mu.Unlock()
+
+	if fresh {
+		return
+	}
+
+	d, err := s.download(ctx, link, loaded, etag, lastModified)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.Equal(checked) {
+		return
+	}
+
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to refresh holiday calendar", "link", link, "error", err)
+
+		// As for requests, the download is retried sooner than a refresh.
+		if loaded {
+			c.checked = s.now().Add(retryInterval - s.refresh)
+		}
+		return
+	}
+
+	c.apply(d, s.now())
+}
+
+// loaded reports whether the calendar was downloaded or seeded, possibly without events.
+func (c *calendar) loaded() bool {
+	return !c.checked.IsZero()
+}
+
+func (s *Service) calendar(link string) *calendar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.calendars[link]
+	if !ok {
+		c = &calendar{}
+		s.calendars[link] = c
+	}
+
+	return c
+}
+
+// load downloads the calendar at link, or revalidates it when it was downloaded before. The caller holds c.mu.
+func (s *Service) load(ctx context.Context, link string, c *calendar) error {
+	d, err := s.download(ctx, link, c.loaded(), c.etag, c.lastModified)
+	if err != nil {
+		return err
+	}
+
+	c.apply(d, s.now())
+	return nil
+}
+
+// download is the result of a calendar download.
+type download struct {
+	events       []*ics.VEvent
+	etag         string
+	lastModified string
+
+	// notModified is set when the calendar was revalidated, its events being left unchanged.
+	notModified bool
+}
+
+// apply stores the download in the calendar. The caller holds c.mu.
+func (c *calendar) apply(d *download, now time.Time) {
+	c.checked = now
+	if d.notModified {
+		return
+	}
+
+	c.events, c.etag, c.lastModified = d.events, d.etag, d.lastModified
+}
+
+// download downloads the calendar at link, revalidating it with the etag and lastModified validators when it was
+// loaded before. file:// links are read from disk.
+func (s *Service) download(ctx context.Context, link string, loaded bool, etag, lastModified string) (*download, error) {
+	slog.InfoContext(ctx, "Loading holiday calendar", "link", link)
+
+	if path, ok := strings.CutPrefix(link, "file://"); ok {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		cal, err := ics.ParseCalendar(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse calendar: %w", err)
+		}
+
+		return &download{events: cal.Events()}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if loaded {
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("holiday calendar request failed: %w", urlErr.Err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && loaded:
+		return &download{notModified: true}, nil
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("holiday calendar responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	cal, err := ics.ParseCalendar(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse calendar: %w", err)
+	}
+
+	return &download{
+		events:       cal.Events(),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
@@ -0,0 +1,402 @@
+package holidays
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+func summaries(events []*ics.VEvent) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.GetProperty(ics.ComponentPropertySummary).Value)
+	}
+	return out
+}
+
+func TestService_Events(t *testing.T) {
+	ctx := context.Background()
+
+	seed, err := os.ReadFile("testdata/catalonia.ics")
+	if err != nil {
+		t.Fatalf("failed to read seed: %v", err)
+	}
+
+	var requests, notModified atomic.Int32
+	var down atomic.Bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(seed)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	svc, err := New(Config{Link: srv.URL, Refresh: time.Hour, HTTPClient: srv.Client()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc.now = func() time.Time { return now }
+
+	events := func(t *testing.T) {
+		t.Helper()
+
+		events, err := svc.Events(ctx, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := summaries(events); len(got) != 2 || got[0] != "Christmas Day" {
+			t.Fatalf("unexpected events %v", got)
+		}
+	}
+
+	t.Run("calendars are downloaded once", func(t *testing.T) {
+		events(t)
+		events(t)
+
+		if n := requests.Load(); n != 1 {
+			t.Errorf("expected a single download, got %d", n)
+		}
+	})
+
+	t.Run("calendars are revalidated after the refresh interval", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		events(t)
+
+		if n := notModified.Load(); n != 1 || requests.Load() != 2 {
+			t.Errorf("expected a conditional request, got %d requests and %d not modified", requests.Load(), n)
+		}
+	})
+
+	t.Run("stale events are served while the upstream is down", func(t *testing.T) {
+		down.Store(true)
+		now = now.Add(time.Hour)
+
+		events(t)
+		events(t)
+
+		if n := requests.Load(); n != 3 {
+			t.Errorf("expected the failed download not to be retried right away, got %d requests", n)
+		}
+
+		now = now.Add(retryInterval)
+		events(t)
+
+		if n := requests.Load(); n != 4 {
+			t.Errorf("expected the download to be retried, got %d requests", n)
+		}
+	})
+
+	t.Run("unknown calendars fail while the upstream is down", func(t *testing.T) {
+		if _, err := svc.Events(ctx, srv.URL+"/other"); err == nil {
+			t.Errorf("expected error, got nil")
+		}
+	})
+}
+
+func TestService_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seed, err := os.ReadFile("testdata/catalonia.ics")
+	if err != nil {
+		t.Fatalf("failed to read seed: %v", err)
+	}
+
+	var requests atomic.Int32
+	downloading, release := make(chan struct{}, 1), make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			downloading <- struct{}{}
+			<-release
+		}
+
+		_, _ = w.Write(seed)
+	}))
+	defer srv.Close()
+
+	const refresh = 10 * time.Millisecond
+
+	var elapsed atomic.Int64
+	start := time.Now()
+
+	svc, err := New(Config{Link: srv.URL, Refresh: refresh, HTTPClient: srv.Client()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc.now = func() time.Time { return start.Add(time.Duration(elapsed.Load())) }
+
+	if _, err := svc.Events(ctx, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go svc.Run(ctx)
+
+	t.Run("fresh calendars are skipped", func(t *testing.T) {
+		time.Sleep(10 * refresh)
+
+		if n := requests.Load(); n != 1 {
+			t.Errorf("expected fresh calendars not to be downloaded, got %d requests", n)
+		}
+	})
+
+	t.Run("calendars are available while downloaded", func(t *testing.T) {
+		elapsed.Add(int64(refresh))
+
+		select {
+		case <-downloading:
+		case <-time.After(time.Second):
+			t.Fatal("expected the stale calendar to be downloaded")
+		}
+
+		// The seed differs from the download, which must not replace it.
+		newer := strings.Replace(string(seed), "St Stephen's Day", "Boxing Day", 1)
+
+		seeded := make(chan error, 1)
+		go func() {
+			seeded <- svc.Seed(srv.URL, strings.NewReader(newer))
+		}()
+
+		select {
+		case err := <-seeded:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Error("expected the calendar not to be locked during the download")
+		}
+
+		close(release)
+	})
+
+	t.Run("downloads older than the events served are dropped", func(t *testing.T) {
+		time.Sleep(10 * refresh)
+
+		events, err := svc.Events(ctx, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := summaries(events); len(got) != 2 || got[1] != "Boxing Day" {
+			t.Errorf("expected the seeded events, got %v", got)
+		}
+	})
+}
+
+func TestService_untilDue(t *testing.T) {
+	const refresh = time.Hour
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc, err := New(Config{Refresh: refresh})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := start
+	svc.now = func() time.Time { return now }
+
+	if got := svc.untilDue(); got != refresh {
+		t.Errorf("without calendars, untilDue() = %v, want %v", got, refresh)
+	}
+
+	seed, err := os.ReadFile("testdata/catalonia.ics")
+	if err != nil {
+		t.Fatalf("failed to read seed: %v", err)
+	}
+
+	if err := svc.Seed("https://example.com/first.ics", strings.NewReader(string(seed))); err != nil {
+		t.Fatalf("failed to seed calendar: %v", err)
+	}
+
+	now = start.Add(15 * time.Minute)
+	if err := svc.Seed("https://example.com/second.ics", strings.NewReader(string(seed))); err != nil {
+		t.Fatalf("failed to seed calendar: %v", err)
+	}
+
+	// Calendars that never loaded do not wake the service up.
+	svc.calendar("https://example.com/missing.ics")
+
+	now = start.Add(45 * time.Minute)
+	if got, want := svc.untilDue(), 15*time.Minute; got != want {
+		t.Errorf("untilDue() = %v, want %v, when the first calendar is due", got, want)
+	}
+
+	now = start.Add(2 * refresh)
+	if got := svc.untilDue(); got != 0 {
+		t.Errorf("untilDue() = %v, want 0, once calendars are overdue", got)
+	}
+}
+
+func TestService_SeedFile(t *testing.T) {
+	// The link is never downloaded, the seed being fresh.
+	svc, err := New(Config{Link: "http://invalid.test/calendar.ics", SeedFile: "testdata/catalonia.ics"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := svc.Events(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := summaries(events); len(got) != 2 || got[1] != "St Stephen's Day" {
+		t.Errorf("unexpected events %v", got)
+	}
+
+	if _, err := New(Config{SeedFile: "testdata/missing.ics"}); err == nil {
+		t.Errorf("expected error for a missing seed file")
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := NewRegistry()
+
+	tests := []struct {
+		name    string
+		country string
+		region  string
+		want    string
+		exact   bool
+		err     string
+	}{
+		{name: "default calendar", want: "ES-CT", exact: true},
+		{name: "country", country: "pt", want: "PT", exact: true},
+		{name: "region code", region: "DE-BY", want: "DE-BY", exact: true},
+		{name: "region of the country", country: "DE", region: "by", want: "DE-BY", exact: true},
+		{name: "region code of the country", country: "de", region: "DE-BY", want: "DE-BY", exact: true},
+		{name: "unknown region falls back to its country", country: "DE", region: "HH", want: "DE"},
+		{name: "unknown region code falls back to its country", region: "DE-HH", want: "DE"},
+		{name: "region code of another country", country: "PT", region: "DE-BY", err: "region DE-BY is not in country PT"},
+		{name: "unknown country", country: "XX", err: "no holiday calendar for country XX"},
+		{name: "region without country", region: "CT", err: "region CT needs a country"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, exact, err := r.Lookup(tt.country, tt.region)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Fatalf("Lookup() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil || c.Code != tt.want || exact != tt.exact {
+				t.Fatalf("Lookup() = %+v, %t, %v, want %s, %t", c, exact, err, tt.want, tt.exact)
+			}
+		})
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendars.json")
+	registry := `{"default": "pt-11", "calendars": {"PT-11": {"name": "Lisbon, Portugal", "file": "lisbon.ics"}}}`
+	if err := os.WriteFile(path, []byte(registry), 0o600); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+
+	r, err := LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, _, err := r.Lookup("", "")
+	if err != nil || c.Name != "Lisbon, Portugal" || c.Link() != "file://"+filepath.Join(filepath.Dir(path), "lisbon.ics") {
+		t.Errorf("unexpected default calendar %+v, %v", c, err)
+	}
+
+	if _, _, err := r.Lookup("ES", "CT"); err != nil {
+		t.Errorf("expected builtin calendars to be kept, got %v", err)
+	}
+
+	// Files are read from disk by the service.
+	seed, _ := filepath.Abs("testdata/catalonia.ics")
+	svc, err := New(Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := svc.Events(context.Background(), (&Calendar{File: seed}).Link())
+	if err != nil || len(events) != 2 {
+		t.Errorf("Events() = %d events, %v, want 2", len(events), err)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	cal, err := ics.ParseCalendar(strings.NewReader(strings.ReplaceAll(`BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:christmas
+DTSTART;VALUE=DATE:20251225
+SUMMARY:Christmas Day
+END:VEVENT
+BEGIN:VEVENT
+UID:easter
+DTSTART;VALUE=DATE:20250418
+DTEND;VALUE=DATE:20250422
+SUMMARY:Easter
+END:VEVENT
+BEGIN:VEVENT
+UID:new-year
+DTSTART;VALUE=DATE:20200101
+RRULE:FREQ=YEARLY
+EXDATE;VALUE=DATE:20260101
+SUMMARY:New Year's Day
+END:VEVENT
+BEGIN:VEVENT
+UID:national-day
+DTSTART;VALUE=DATE:20250911
+SUMMARY:National Day of Catalonia
+END:VEVENT
+END:VCALENDAR
+`, "\n", "\r\n")))
+	if err != nil {
+		t.Fatalf("failed to parse calendar: %v", err)
+	}
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	var got []string
+	for _, h := range Occurrences(cal.Events(), day("2025-04-20"), day("2027-01-01")) {
+		got = append(got, h.Start.Format(time.DateOnly)+"/"+h.End.Format(time.DateOnly)+" "+h.Name)
+	}
+
+	// Easter overlaps the window, and New Year's Day 2026 is excluded.
+	want := []string{
+		"2025-04-18/2025-04-21 Easter",
+		"2025-09-11/2025-09-11 National Day of Catalonia",
+		"2025-12-25/2025-12-25 Christmas Day",
+		"2027-01-01/2027-01-01 New Year's Day",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Occurrences() = %v, want %v", got, want)
+	}
+}
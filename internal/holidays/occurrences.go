@@ -0,0 +1,121 @@
+package holidays
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/teambition/rrule-go"
+)
+
+// Holiday is an occurrence of a holiday event. Holidays span whole days: Start and End are the first and last days,
+// at midnight UTC.
+type Holiday struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Days returns the number of days of the holiday.
+func (h Holiday) Days() int {
+	return int(h.End.Sub(h.Start).Hours()/24) + 1
+}
+
+// Occurrences returns the holidays of the events overlapping the days from and to, inclusive, sorted by start date.
+// Recurring events are expanded with their RRULE and EXDATE properties. Events without a valid start are skipped.
+func Occurrences(events []*ics.VEvent, from, to time.Time) []Holiday {
+	from, to = Date(from), Date(to)
+
+	var out []Holiday
+	for _, event := range events {
+		start, end, ok := eventDays(event)
+		if !ok {
+			continue
+		}
+
+		name := ""
+		if p := event.GetProperty(ics.ComponentPropertySummary); p != nil {
+			name = p.Value
+		}
+
+		for _, day := range occurrenceStarts(event, start, from.Add(start.Sub(end)), to) {
+			h := Holiday{Name: name, Start: day, End: day.Add(end.Sub(start))}
+			if !h.End.Before(from) && !h.Start.After(to) {
+				out = append(out, h)
+			}
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b Holiday) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.Name, b.Name))
+	})
+
+	return out
+}
+
+// Date returns the day of t, in its own location, at midnight UTC.
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// eventDays returns the first and last days of the first occurrence of the event. DTEND is exclusive, an event
+// without one lasts a day.
+func eventDays(event *ics.VEvent) (time.Time, time.Time, bool) {
+	start, err := event.GetAllDayStartAt()
+	if err != nil {
+		if start, err = event.GetStartAt(); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	start = Date(start)
+
+	end := start
+	if e, err := event.GetAllDayEndAt(); err == nil {
+		if e = Date(e).AddDate(0, 0, -1); e.After(start) {
+			end = e
+		}
+	}
+
+	return start, end, true
+}
+
+// occurrenceStarts returns the first days of the occurrences of the event starting between the days from and to.
+func occurrenceStarts(event *ics.VEvent, start, from, to time.Time) []time.Time {
+	p := event.GetProperty(ics.ComponentPropertyRrule)
+	if p == nil {
+		return []time.Time{start}
+	}
+
+	opt, err := rrule.StrToROption(p.Value)
+	if err != nil {
+		// An unsupported rule leaves the first occurrence.
+		return []time.Time{start}
+	}
+	opt.Dtstart = start
+
+	rule, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return []time.Time{start}
+	}
+
+	excluded := map[time.Time]bool{}
+	for _, exdate := range event.GetProperties(ics.ComponentPropertyExdate) {
+		dates, err := rrule.StrToDates(exdate.Value)
+		if err != nil {
+			continue
+		}
+		for _, d := range dates {
+			excluded[Date(d)] = true
+		}
+	}
+
+	var days []time.Time
+	for _, t := range rule.Between(from, to, true) {
+		if day := Date(t); !excluded[day] {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
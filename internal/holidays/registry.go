@@ -0,0 +1,198 @@
+package holidays
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultCode is the calendar of requests that do not name a country.
+const DefaultCode = "ES-CT"
+
+// Calendar is the holiday calendar of a country or region.
+type Calendar struct {
+	// Code is the ISO 3166-1 alpha-2 code of the country, e.g. PT, or the ISO 3166-2 code of the region, e.g. ES-CT.
+	Code string `json:"-"`
+
+	// Name is the human readable name of the country or region, e.g. "Catalonia, Spain".
+	Name string `json:"name"`
+
+	// URL or File is the ICS source of the calendar. Relative files are resolved against the registry file.
+	URL  string `json:"url"`
+	File string `json:"file"`
+}
+
+// Link returns the link the calendar is loaded from by the Service, a file:// link for files.
+func (c *Calendar) Link() string {
+	if c.File != "" {
+		return "file://" + c.File
+	}
+
+	return c.URL
+}
+
+// builtinCalendars are the calendars known without a registry file.
+var builtinCalendars = map[string]*Calendar{
+	"ES":    {Name: "Spain", URL: "https://www.officeholidays.com/ics/spain"},
+	"ES-CT": {Name: "Catalonia, Spain", URL: DefaultLink},
+	"ES-MD": {Name: "Madrid, Spain", URL: "https://www.officeholidays.com/ics/spain/madrid"},
+	"PT":    {Name: "Portugal", URL: "https://www.officeholidays.com/ics/portugal"},
+	"FR":    {Name: "France", URL: "https://www.officeholidays.com/ics/france"},
+	"IT":    {Name: "Italy", URL: "https://www.officeholidays.com/ics/italy"},
+	"NL":    {Name: "Netherlands", URL: "https://www.officeholidays.com/ics/netherlands"},
+	"DE":    {Name: "Germany", URL: "https://www.officeholidays.com/ics/germany"},
+	"DE-BE": {Name: "Berlin, Germany", URL: "https://www.officeholidays.com/ics/germany/berlin"},
+	"DE-BY": {Name: "Bavaria, Germany", URL: "https://www.officeholidays.com/ics/germany/bavaria"},
+	"US":    {Name: "United States", URL: "https://www.officeholidays.com/ics/usa"},
+}
+
+// Registry maps country and region codes to their holiday calendars.
+type Registry struct {
+	// Default is the code of the calendar of requests that do not name a country.
+	Default   string               `json:"default"`
+	Calendars map[string]*Calendar `json:"calendars"`
+}
+
+// NewRegistry returns the registry of the builtin calendars.
+func NewRegistry() *Registry {
+	r := &Registry{Default: DefaultCode, Calendars: map[string]*Calendar{}}
+	for code, c := range builtinCalendars {
+		copied := *c
+		copied.Code = code
+		r.Calendars[code] = &copied
+	}
+
+	return r
+}
+
+// LoadRegistry returns the builtin calendars extended, and overridden, by those of the registry file at path.
+func LoadRegistry(path string) (*Registry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file Registry
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("invalid holiday calendar registry %s: %w", path, err)
+	}
+
+	r := NewRegistry()
+	for code, c := range file.Calendars {
+		if c == nil || (c.URL == "") == (c.File == "") {
+			return nil, fmt.Errorf("invalid holiday calendar registry %s: calendar %q needs either a url or a file", path, code)
+		}
+
+		if c.File != "" && !filepath.IsAbs(c.File) {
+			c.File = filepath.Join(filepath.Dir(path), c.File)
+		}
+
+		c.Code = strings.ToUpper(code)
+		r.Calendars[c.Code] = c
+	}
+
+	if file.Default != "" {
+		r.Default = strings.ToUpper(file.Default)
+	}
+
+	if _, ok := r.Calendars[r.Default]; !ok {
+		return nil, fmt.Errorf("invalid holiday calendar registry %s: unknown default calendar %q", path, r.Default)
+	}
+
+	return r, nil
+}
+
+// RegistryFromEnv loads the registry file at HOLIDAY_CALENDARS_FILE, or returns the builtin calendars when it is not
+// set. HOLIDAY_CALENDAR_LINK replaces the source of the default calendar.
+func RegistryFromEnv() (*Registry, error) {
+	r := NewRegistry()
+	if path := os.Getenv("HOLIDAY_CALENDARS_FILE"); path != "" {
+		var err error
+		if r, err = LoadRegistry(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if link := os.Getenv("HOLIDAY_CALENDAR_LINK"); link != "" {
+		c := *r.Calendars[r.Default]
+		c.URL, c.File = link, ""
+		r.Calendars[r.Default] = &c
+	}
+
+	return r, nil
+}
+
+// Lookup returns the calendar of the region, given as an ISO 3166-2 code or the part after the country code, or
+// else of the country. The default calendar is returned when neither is given. A region without a calendar of its own
+// falls back to the calendar of its country, which leaves out the regional holidays: exact is then false.
+func (r *Registry) Lookup(country, region string) (c *Calendar, exact bool, err error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.ToUpper(strings.TrimSpace(region))
+
+	if prefix, _, ok := strings.Cut(region, "-"); ok {
+		if country != "" && country != prefix {
+			return nil, false, fmt.Errorf("region %s is not in country %s", region, country)
+		}
+		country = prefix
+	} else if region != "" {
+		if country == "" {
+			return nil, false, fmt.Errorf("region %s needs a country", region)
+		}
+		region = country + "-" + region
+	}
+
+	if c, ok := r.Calendars[cmp.Or(region, country, r.Default)]; ok {
+		return c, true, nil
+	}
+
+	if c, ok := r.Calendars[country]; ok && region != "" {
+		return c, false, nil
+	}
+
+	if country == "" {
+		return nil, false, fmt.Errorf("unknown holiday calendar %s", r.Default)
+	}
+
+	var known []string
+	for _, k := range slices.Sorted(maps.Keys(r.Calendars)) {
+		if k == country || strings.HasPrefix(k, country+"-") {
+			known = append(known, k)
+		}
+	}
+
+	if len(known) == 0 {
+		return nil, false, fmt.Errorf("no holiday calendar for country %s, available countries: %s", country, strings.Join(r.countries(), ", "))
+	}
+
+	return nil, false, fmt.Errorf("no holiday calendar for %s, available: %s", cmp.Or(region, country), strings.Join(known, ", "))
+}
+
+// ByLink returns the calendar loaded from link, if any.
+func (r *Registry) ByLink(link string) (*Calendar, bool) {
+	for _, c := range r.Calendars {
+		if c.Link() == link {
+			return c, true
+		}
+	}
+
+	return nil, false
+}
+
+// countries returns the sorted codes of the countries with a calendar.
+func (r *Registry) countries() []string {
+	var codes []string
+	for code := range r.Calendars {
+		country, _, _ := strings.Cut(code, "-")
+		if !slices.Contains(codes, country) {
+			codes = append(codes, country)
+		}
+	}
+
+	slices.Sort(codes)
+	return codes
+}
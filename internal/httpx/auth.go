@@ -0,0 +1,143 @@
+package httpx
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/twitchtv/twirp"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller: the user an API key belongs to, or the sub claim of a JWT. It is empty when
+	// authentication is disabled.
+	Subject string
+
+	// Claims are the claims of the JWT the caller authenticated with, nil for API keys.
+	Claims map[string]any
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal of the request, as set by Auth.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// AuthConfig lists the credentials Auth accepts.
+type AuthConfig struct {
+	// APIKeys maps API keys to the user they belong to.
+	APIKeys map[string]string
+
+	// JWTSecret is the key of HMAC signed JWTs (HS256, HS384 or HS512). JWTs are rejected when it is empty.
+	JWTSecret []byte
+
+	// JWTIssuer and JWTAudience, when set, must match the iss and aud claims of JWTs.
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// AuthConfigFromEnv reads the API keys from AUTH_API_KEYS, as comma separated user=key pairs, and the JWT settings
+// from AUTH_JWT_SECRET, AUTH_JWT_ISSUER and AUTH_JWT_AUDIENCE.
+func AuthConfigFromEnv() AuthConfig {
+	cfg := AuthConfig{
+		APIKeys:     map[string]string{},
+		JWTSecret:   []byte(os.Getenv("AUTH_JWT_SECRET")),
+		JWTIssuer:   os.Getenv("AUTH_JWT_ISSUER"),
+		JWTAudience: os.Getenv("AUTH_JWT_AUDIENCE"),
+	}
+
+	for _, pair := range strings.Split(os.Getenv("AUTH_API_KEYS"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		user, key, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || user == "" || key == "" {
+			slog.Warn("Ignoring invalid AUTH_API_KEYS entry, expected user=key")
+			continue
+		}
+
+		cfg.APIKeys[key] = user
+	}
+
+	return cfg
+}
+
+// Enabled reports whether any credential is configured.
+func (c AuthConfig) Enabled() bool {
+	return len(c.APIKeys) > 0 || len(c.JWTSecret) > 0
+}
+
+// Auth authenticates requests with an API key, given in the X-API-Key header or as a bearer token, or with a JWT
+// bearer token, and stores the caller in the request context, see PrincipalFrom. Other requests are rejected with a
+// twirp.Unauthenticated error. When no credential is configured, every request runs as an anonymous principal.
+func Auth(cfg AuthConfig) func(handler http.Handler) http.Handler {
+	// Keys are compared by their hashes in constant time, so that timing does not leak them.
+	keys := map[[sha256.Size]byte]string{}
+	for key, user := range cfg.APIKeys {
+		keys[sha256.Sum256([]byte(key))] = user
+	}
+
+	authenticate := func(r *http.Request) (*Principal, error) {
+		if !cfg.Enabled() {
+			return &Principal{}, nil
+		}
+
+		token := r.Header.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token == "" {
+			token = strings.TrimSpace(bearer)
+		}
+
+		if token == "" {
+			return nil, twirp.Unauthenticated.Error("missing credentials")
+		}
+
+		// JWTs are the only tokens made of three dot separated parts.
+		if strings.Count(token, ".") == 2 && len(cfg.JWTSecret) > 0 {
+			claims, err := verifyJWT(token, cfg.JWTSecret, cfg.JWTIssuer, cfg.JWTAudience, time.Now())
+			if err != nil {
+				return nil, twirp.Unauthenticated.Errorf("invalid token: %v", err)
+			}
+
+			sub, _ := claims["sub"].(string)
+			return &Principal{Subject: sub, Claims: claims}, nil
+		}
+
+		sum := sha256.Sum256([]byte(token))
+		for hash, user := range keys {
+			if subtle.ConstantTimeCompare(hash[:], sum[:]) == 1 {
+				return &Principal{Subject: user}, nil
+			}
+		}
+
+		return nil, twirp.Unauthenticated.Error("invalid API key")
+	}
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := authenticate(r)
+			if err != nil {
+				slog.InfoContext(r.Context(), "HTTP request rejected", "http_method", r.Method, "http_path", r.URL.Path, "error", err)
+
+				w.Header().Set("WWW-Authenticate", `Bearer realm="acai"`)
+				_ = twirp.WriteError(w, err)
+				return
+			}
+
+			handler.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
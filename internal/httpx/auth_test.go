@@ -0,0 +1,127 @@
+package httpx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signJWT(t *testing.T, secret string, header, claims map[string]any) string {
+	t.Helper()
+
+	segment := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to encode token: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	unsigned := segment(header) + "." + segment(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuth(t *testing.T) {
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	auth := Auth(AuthConfig{
+		APIKeys:     map[string]string{"key-1": "alice"},
+		JWTSecret:   []byte("secret"),
+		JWTIssuer:   "acai",
+		JWTAudience: "chat",
+	})
+
+	var got *Principal
+	record := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = PrincipalFrom(r.Context())
+	})
+	handler := auth(record)
+
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		subject string
+	}{
+		{name: "API key header", header: "X-API-Key", value: "key-1", subject: "alice"},
+		{name: "API key bearer", header: "Authorization", value: "Bearer key-1", subject: "alice"},
+		{name: "unknown API key", header: "X-API-Key", value: "key-2"},
+		{name: "missing credentials"},
+		{
+			name:    "valid JWT",
+			header:  "Authorization",
+			value:   "Bearer " + signJWT(t, "secret", hs256, map[string]any{"sub": "bob", "iss": "acai", "aud": []string{"chat"}, "exp": exp}),
+			subject: "bob",
+		},
+		{
+			name:   "JWT signed with another secret",
+			header: "Authorization",
+			value:  "Bearer " + signJWT(t, "other", hs256, map[string]any{"sub": "bob", "iss": "acai", "aud": "chat", "exp": exp}),
+		},
+		{
+			name:   "expired JWT",
+			header: "Authorization",
+			value:  "Bearer " + signJWT(t, "secret", hs256, map[string]any{"sub": "bob", "iss": "acai", "aud": "chat", "exp": time.Now().Add(-time.Hour).Unix()}),
+		},
+		{
+			name:   "JWT for another audience",
+			header: "Authorization",
+			value:  "Bearer " + signJWT(t, "secret", hs256, map[string]any{"sub": "bob", "iss": "acai", "aud": "billing", "exp": exp}),
+		},
+		{
+			name:   "JWT without subject",
+			header: "Authorization",
+			value:  "Bearer " + signJWT(t, "secret", hs256, map[string]any{"iss": "acai", "aud": "chat", "exp": exp}),
+		},
+		{
+			name:   "unsigned JWT",
+			header: "Authorization",
+			value:  "Bearer " + signJWT(t, "secret", map[string]any{"alg": "none"}, map[string]any{"sub": "bob", "iss": "acai", "aud": "chat", "exp": exp}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+
+			req := httptest.NewRequest(http.MethodPost, "/twirp/acai.chat.ChatService/ListConversations", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.subject == "" {
+				if rec.Code != http.StatusUnauthorized || got != nil {
+					t.Errorf("expected request to be rejected, got status %d and principal %+v", rec.Code, got)
+				}
+				return
+			}
+
+			if got == nil || got.Subject != tt.subject {
+				t.Errorf("principal = %+v, want subject %q", got, tt.subject)
+			}
+		})
+	}
+
+	t.Run("disabled authentication runs requests anonymously", func(t *testing.T) {
+		got = nil
+
+		rec := httptest.NewRecorder()
+		Auth(AuthConfig{})(record).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/twirp/", nil))
+
+		if got == nil || got.Subject != "" {
+			t.Errorf("expected anonymous principal, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package httpx
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/twitchtv/twirp"
+)
+
+const (
+	defaultRateLimitRPS   = 2
+	defaultRateLimitBurst = 20
+)
+
+// RateLimit is a token bucket: callers may send Burst requests at once, and then Rate requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitFromEnv reads the rate limit from RATE_LIMIT_RPS and RATE_LIMIT_BURST. A rate of 0 disables it.
+func RateLimitFromEnv() RateLimit {
+	limit := RateLimit{Rate: defaultRateLimitRPS, Burst: defaultRateLimitBurst}
+
+	if v := os.Getenv("RATE_LIMIT_RPS"); v != "" {
+		if n, err := strconv.ParseFloat(v, 64); err == nil && n >= 0 {
+			limit.Rate = n
+		} else {
+			slog.Warn("Ignoring invalid RATE_LIMIT_RPS", "value", v)
+		}
+	}
+
+	if v := os.Getenv("RATE_LIMIT_BURST"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit.Burst = n
+		} else {
+			slog.Warn("Ignoring invalid RATE_LIMIT_BURST", "value", v)
+		}
+	}
+
+	return limit
+}
+
+// RateLimitBackend holds the token buckets of the callers. Backends shared between the instances of a deployment,
+// e.g. on Redis, enforce the limit across all of them.
+type RateLimitBackend interface {
+	// Take takes a token from the bucket of the key, and otherwise reports how long to wait for the next one.
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (ok bool, retryAfter time.Duration, err error)
+}
+
+// MemoryBuckets is a RateLimitBackend local to the process.
+type MemoryBuckets struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewMemoryBuckets() *MemoryBuckets {
+	return &MemoryBuckets{buckets: map[string]*bucket{}}
+}
+
+// sweepEvery is the number of takes between two sweeps of the full buckets, which need not be kept.
+const sweepEvery = 1000
+
+func (m *MemoryBuckets) Take(ctx context.Context, key string, limit RateLimit, now time.Time) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.takes++; m.takes%sweepEvery == 0 {
+		for k, b := range m.buckets {
+			if b.refill(limit, now) >= float64(limit.Burst) {
+				delete(m.buckets, k)
+			}
+		}
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		m.buckets[key] = b
+	}
+
+	if b.refill(limit, now) < 1 {
+		wait := time.Duration(math.Ceil((1 - b.tokens) / limit.Rate * float64(time.Second)))
+		return false, wait, nil
+	}
+
+	b.tokens--
+	return true, 0, nil
+}
+
+// refill adds the tokens earned since the last update, up to the burst, and returns the available tokens.
+func (b *bucket) refill(limit RateLimit, now time.Time) float64 {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.updated = now
+	}
+
+	return b.tokens
+}
+
+// RateLimiter limits the requests of each caller, identified by the principal set by Auth or else by their IP address.
+// Rejected requests fail with a twirp.ResourceExhausted error, whose retry_after metadata and Retry-After header give
+// the number of seconds to wait. Requests are let through when the backend fails.
+func RateLimiter(backend RateLimitBackend, limit RateLimit) func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		if limit.Rate <= 0 {
+			return handler
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := callerKey(r)
+
+			ok, wait, err := backend.Take(r.Context(), key, limit, time.Now())
+			if err != nil {
+				slog.ErrorContext(r.Context(), "Rate limit backend failed", "error", err)
+			} else if !ok {
+				retryAfter := strconv.Itoa(int(math.Ceil(wait.Seconds())))
+				slog.InfoContext(r.Context(), "HTTP request rate limited", "http_method", r.Method, "http_path", r.URL.Path, "caller", key, "retry_after", retryAfter)
+
+				w.Header().Set("Retry-After", retryAfter)
+				_ = twirp.WriteError(w, twirp.NewError(twirp.ResourceExhausted, "too many requests, please retry later").
+					WithMeta("retry_after", retryAfter))
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// callerKey identifies the caller of the request for rate limiting.
+func callerKey(r *http.Request) string {
+	if p, ok := PrincipalFrom(r.Context()); ok && p.Subject != "" {
+		return "user:" + p.Subject
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
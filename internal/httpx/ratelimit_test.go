@@ -0,0 +1,86 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryBuckets(t *testing.T) {
+	ctx := context.Background()
+	limit := RateLimit{Rate: 2, Burst: 3}
+	now := time.Now()
+
+	buckets := NewMemoryBuckets()
+	for i := range limit.Burst {
+		if ok, _, _ := buckets.Take(ctx, "alice", limit, now); !ok {
+			t.Fatalf("request %d of the burst rejected", i+1)
+		}
+	}
+
+	ok, wait, err := buckets.Take(ctx, "alice", limit, now)
+	if err != nil || ok || wait != 500*time.Millisecond {
+		t.Fatalf("Take() = %v, %v, %v, want rejection for 500ms", ok, wait, err)
+	}
+
+	if ok, _, _ := buckets.Take(ctx, "bob", limit, now); !ok {
+		t.Errorf("expected buckets to be per key")
+	}
+
+	if ok, _, _ := buckets.Take(ctx, "alice", limit, now.Add(500*time.Millisecond)); !ok {
+		t.Errorf("expected a token to be refilled after 500ms")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	handler := RateLimiter(NewMemoryBuckets(), RateLimit{Rate: 0.1, Burst: 1})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(subject, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/twirp/acai.chat.ChatService/StartConversation", nil)
+		req.RemoteAddr = addr
+		if subject != "" {
+			req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: subject}))
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("alice", "10.0.0.1:1234"); rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec := send("alice", "10.0.0.2:1234")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "10" {
+		t.Fatalf("expected rate limited request with Retry-After 10, got status %d and %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	var body struct {
+		Code string            `json:"code"`
+		Meta map[string]string `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code != "resource_exhausted" || body.Meta["retry_after"] != "10" {
+		t.Errorf("unexpected error body %s", rec.Body)
+	}
+
+	if rec := send("bob", "10.0.0.1:1234"); rec.Code != http.StatusOK {
+		t.Errorf("expected other users not to be limited, got status %d", rec.Code)
+	}
+
+	// Anonymous callers are told apart by their address.
+	if rec := send("", "10.0.0.1:1234"); rec.Code != http.StatusOK {
+		t.Errorf("first anonymous request status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if rec := send("", "10.0.0.1:5678"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second anonymous request status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	if rec := send("", "10.0.0.3:1234"); rec.Code != http.StatusOK {
+		t.Errorf("expected other addresses not to be limited, got status %d", rec.Code)
+	}
+}
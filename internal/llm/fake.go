@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+var _ Provider = (*Fake)(nil)
+
+// Fake is a scriptable in-process Provider for tests and local demos. Scripted responses are returned in order;
+// once the script is exhausted the fake echoes the last user message back. All requests are recorded. Responses
+// without usage report an estimate of the tokens of the request and the message.
+type Fake struct {
+	mu       sync.Mutex
+	script   []fakeStep
+	requests []Request
+}
+
+type fakeStep struct {
+	resp *Response
+	err  error
+}
+
+func NewFake(responses ...Response) *Fake {
+	f := &Fake{}
+	for _, r := range responses {
+		f.Push(r)
+	}
+
+	return f
+}
+
+// Push appends a response to the script.
+func (f *Fake) Push(resp Response) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.script = append(f.script, fakeStep{resp: &resp})
+	return f
+}
+
+// PushError appends a failing completion to the script.
+func (f *Fake) PushError(err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.script = append(f.script, fakeStep{err: err})
+	return f
+}
+
+// Requests returns all requests received so far.
+func (f *Fake) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Request(nil), f.requests...)
+}
+
+func (f *Fake) Complete(ctx context.Context, req Request, onDelta func(string)) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+
+	var step fakeStep
+	if len(f.script) > 0 {
+		step, f.script = f.script[0], f.script[1:]
+	} else {
+		step = fakeStep{resp: echo(req)}
+	}
+	f.mu.Unlock()
+
+	if step.err != nil {
+		return nil, step.err
+	}
+
+	if onDelta != nil && step.resp.Message.Content != "" {
+		for _, word := range strings.SplitAfter(step.resp.Message.Content, " ") {
+			onDelta(word)
+		}
+	}
+
+	resp := *step.resp
+	if resp.Message.Role == "" {
+		resp.Message.Role = RoleAssistant
+	}
+
+	if resp.Model == "" {
+		resp.Model = req.Model
+	}
+
+	if resp.Usage == (Usage{}) {
+		prompt := int64(CountTokens(req.Model, req.Messages...) + CountToolTokens(req.Model, req.Tools...))
+		completion := int64(CountTokens(req.Model, resp.Message))
+		resp.Usage = Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+	}
+
+	return &resp, nil
+}
+
+func echo(req Request) *Response {
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == RoleUser {
+			return &Response{Message: Message{Role: RoleAssistant, Content: "You said: " + req.Messages[i].Content}}
+		}
+	}
+
+	return &Response{Message: Message{Role: RoleAssistant, Content: "Hello!"}}
+}
@@ -0,0 +1,86 @@
+// Package llm abstracts chat completion providers, so the assistant can run against OpenAI, Azure OpenAI, any
+// OpenAI compatible model server, or an in-process fake.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+)
+
+// Message is a single provider agnostic chat message.
+type Message struct {
+	Role    Role
+	Content string
+
+	// ToolCalls are the tools requested by an assistant message.
+	ToolCalls []ToolCall
+
+	// ToolCallID links a tool message to the call it answers.
+	ToolCallID string
+}
+
+// ToolCall is a tool invocation requested by the model.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// Tool describes a function the model may call, with its parameters as a JSON schema.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+type Request struct {
+	Model    string
+	Messages []Message
+	Tools    []Tool
+}
+
+type Response struct {
+	Message Message
+
+	// Model is the model that generated the message, as reported by the provider.
+	Model string
+	Usage Usage
+}
+
+// Usage is the number of tokens a completion took.
+type Usage struct {
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+}
+
+type Provider interface {
+	// Complete generates the next message for the request. When onDelta is not nil the completion is streamed and
+	// onDelta receives the content as soon as it is produced; the returned response always holds the full message.
+	Complete(ctx context.Context, req Request, onDelta func(string)) (*Response, error)
+}
+
+// NewFromEnv creates the provider selected by the LLM_PROVIDER environment variable: "openai" (default), "azure" or
+// "fake".
+func NewFromEnv() (Provider, error) {
+	switch name := os.Getenv("LLM_PROVIDER"); name {
+	case "", "openai":
+		return NewOpenAI(), nil
+	case "azure":
+		return NewAzure(os.Getenv("AZURE_OPENAI_ENDPOINT"), os.Getenv("AZURE_OPENAI_DEPLOYMENT"), os.Getenv("AZURE_OPENAI_API_VERSION"), os.Getenv("AZURE_OPENAI_API_KEY"))
+	case "fake":
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", name)
+	}
+}
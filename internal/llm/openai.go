@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+)
+
+var _ Provider = (*OpenAI)(nil)
+
+// OpenAI is a Provider backed by the OpenAI chat completions API or any API compatible with it.
+type OpenAI struct {
+	cli openai.Client
+}
+
+// NewOpenAI creates an OpenAI provider. The client is configured from the environment (OPENAI_API_KEY,
+// OPENAI_BASE_URL to target a compatible model server), and opts are applied on top.
+func NewOpenAI(opts ...option.RequestOption) *OpenAI {
+	return &OpenAI{cli: openai.NewClient(opts...)}
+}
+
+// NewAzure creates an OpenAI provider targeting an Azure OpenAI deployment.
+func NewAzure(endpoint, deployment, apiVersion, apiKey string) (*OpenAI, error) {
+	if endpoint == "" || deployment == "" || apiVersion == "" {
+		return nil, errors.New("azure provider requires an endpoint, a deployment and an API version")
+	}
+
+	return NewOpenAI(
+		option.WithBaseURL(strings.TrimSuffix(endpoint, "/")+"/openai/deployments/"+deployment+"/"),
+		option.WithQueryAdd("api-version", apiVersion),
+		option.WithHeader("api-key", apiKey),
+	), nil
+}
+
+func (o *OpenAI) Complete(ctx context.Context, req Request, onDelta func(string)) (*Response, error) {
+	params := openai.ChatCompletionNewParams{
+		Model: req.Model,
+	}
+
+	for _, m := range req.Messages {
+		params.Messages = append(params.Messages, toOpenAIMessage(m))
+	}
+
+	for _, t := range req.Tools {
+		params.Tools = append(params.Tools, openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+			Name:        t.Name,
+			Description: openai.String(t.Description),
+			Parameters:  t.Parameters,
+		}))
+	}
+
+	if onDelta == nil {
+		resp, err := o.cli.Chat.Completions.New(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp.Choices) == 0 {
+			return nil, errors.New("no choices returned by OpenAI")
+		}
+
+		return &Response{Message: fromOpenAIMessage(resp.Choices[0].Message), Model: resp.Model, Usage: fromOpenAIUsage(resp.Usage)}, nil
+	}
+
+	// Usage is only reported at the end of a stream when asked for.
+	params.StreamOptions.IncludeUsage = openai.Bool(true)
+
+	stream := o.cli.Chat.Completions.NewStreaming(ctx, params)
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	acc := openai.ChatCompletionAccumulator{}
+	for stream.Next() {
+		chunk := stream.Current()
+		acc.AddChunk(chunk)
+
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			onDelta(chunk.Choices[0].Delta.Content)
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(acc.Choices) == 0 {
+		return nil, errors.New("no choices returned by OpenAI")
+	}
+
+	return &Response{Message: fromOpenAIMessage(acc.Choices[0].Message), Model: acc.Model, Usage: fromOpenAIUsage(acc.Usage)}, nil
+}
+
+func toOpenAIMessage(m Message) openai.ChatCompletionMessageParamUnion {
+	switch m.Role {
+	case RoleSystem:
+		return openai.SystemMessage(m.Content)
+	case RoleAssistant:
+		param := openai.ChatCompletionAssistantMessageParam{}
+		if m.Content != "" {
+			param.Content.OfString = openai.String(m.Content)
+		}
+
+		for _, call := range m.ToolCalls {
+			param.ToolCalls = append(param.ToolCalls, openai.ChatCompletionMessageToolCallUnionParam{
+				OfFunction: &openai.ChatCompletionMessageFunctionToolCallParam{
+					ID: call.ID,
+					Function: openai.ChatCompletionMessageFunctionToolCallFunctionParam{
+						Name:      call.Name,
+						Arguments: call.Arguments,
+					},
+				},
+			})
+		}
+
+		return openai.ChatCompletionMessageParamUnion{OfAssistant: &param}
+	case RoleTool:
+		return openai.ToolMessage(m.Content, m.ToolCallID)
+	default:
+		return openai.UserMessage(m.Content)
+	}
+}
+
+// fromOpenAIMessage reads the fields directly rather than through the union helpers, as those rely on raw JSON that
+// is not set on messages built by openai.ChatCompletionAccumulator.
+func fromOpenAIMessage(m openai.ChatCompletionMessage) Message {
+	msg := Message{Role: RoleAssistant, Content: m.Content}
+
+	for _, call := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+
+	return msg
+}
+
+func fromOpenAIUsage(u openai.CompletionUsage) Usage {
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.TotalTokens}
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// Price is the cost of a model in US dollars per million tokens.
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// PriceTable holds the prices of models by name.
+type PriceTable map[string]Price
+
+// DefaultPrices are the list prices of the OpenAI models at the time of writing.
+var DefaultPrices = PriceTable{
+	"gpt-4.1":      {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini": {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano": {Input: 0.10, Output: 0.40},
+	"gpt-4o":       {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":  {Input: 0.15, Output: 0.60},
+	"o1":           {Input: 15.00, Output: 60.00},
+	"o1-mini":      {Input: 1.10, Output: 4.40},
+	"o3":           {Input: 2.00, Output: 8.00},
+	"o3-mini":      {Input: 1.10, Output: 4.40},
+	"o4-mini":      {Input: 1.10, Output: 4.40},
+}
+
+// PricesFromEnv returns the default prices, overridden and extended by the JSON object in the file at LLM_PRICES_FILE,
+// e.g. {"gpt-4.1": {"input": 2.0, "output": 8.0}}.
+func PricesFromEnv() (PriceTable, error) {
+	prices := maps.Clone(DefaultPrices)
+
+	path := os.Getenv("LLM_PRICES_FILE")
+	if path == "" {
+		return prices, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return prices, err
+	}
+
+	var custom PriceTable
+	if err := json.Unmarshal(b, &custom); err != nil {
+		return prices, fmt.Errorf("invalid price table %s: %w", path, err)
+	}
+
+	maps.Copy(prices, custom)
+	return prices, nil
+}
+
+// Cost estimates the cost of the usage in US dollars. Models are looked up by their longest matching prefix, so that
+// snapshots like gpt-4.1-2025-04-14 use the price of their model. It reports false for unknown models.
+func (t PriceTable) Cost(model string, u Usage) (float64, bool) {
+	var price Price
+	match := ""
+
+	for name, p := range t {
+		if strings.HasPrefix(model, name) && len(name) > len(match) {
+			price, match = p, name
+		}
+	}
+
+	if match == "" {
+		return 0, false
+	}
+
+	return (float64(u.PromptTokens)*price.Input + float64(u.CompletionTokens)*price.Output) / 1e6, true
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Token counts are estimated from the text rather than computed with the model's tokenizer, which is close enough to
+// keep requests within a budget. English text averages about 4 characters per token, a bit more with the o200k
+// encoding of recent models than with the cl100k encoding of older ones, while other scripts take about a token per
+// character.
+type encoding struct {
+	charsPerToken float64
+}
+
+var (
+	o200k  = encoding{charsPerToken: 4.2}
+	cl100k = encoding{charsPerToken: 3.8}
+)
+
+// messageOverhead is the number of tokens a message costs on top of its content, for its role and delimiters.
+const messageOverhead = 4
+
+var reasoningModel = regexp.MustCompile(`^o\d`)
+
+func encodingFor(model string) encoding {
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"), strings.HasPrefix(model, "gpt-4.1"), strings.HasPrefix(model, "gpt-5"),
+		reasoningModel.MatchString(model):
+		return o200k
+	default:
+		return cl100k
+	}
+}
+
+func (e encoding) count(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+
+	return int(math.Ceil(float64(ascii)/e.charsPerToken)) + other
+}
+
+// CountTokens estimates the number of tokens the messages take in a request to the given model.
+func CountTokens(model string, messages ...Message) int {
+	enc := encodingFor(model)
+
+	n := 0
+	for _, m := range messages {
+		n += messageOverhead + enc.count(m.Content) + enc.count(m.ToolCallID)
+		for _, c := range m.ToolCalls {
+			n += enc.count(c.ID) + enc.count(c.Name) + enc.count(c.Arguments)
+		}
+	}
+
+	return n
+}
+
+// CountToolTokens estimates the number of tokens the tool definitions take in a request to the given model.
+func CountToolTokens(model string, tools ...Tool) int {
+	enc := encodingFor(model)
+
+	n := 0
+	for _, t := range tools {
+		params, _ := json.Marshal(t.Parameters)
+		n += messageOverhead + enc.count(t.Name) + enc.count(t.Description) + enc.count(string(params))
+	}
+
+	return n
+}
@@ -3,7 +3,9 @@ package observability
 import (
 	"context"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -23,3 +25,21 @@ func InitTracer() (func(context.Context) error, error) {
 
 	return tp.Shutdown, nil
 }
+
+// InitMeter exports the metrics, like the weather cache hits and misses, to the same collector as the traces.
+func InitMeter() (func(context.Context) error, error) {
+	exporter, err := otlpmetrichttp.New(context.Background(),
+		otlpmetrichttp.WithEndpoint("localhost:4318"),
+		otlpmetrichttp.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := metric.NewMeterProvider(
+		metric.WithReader(metric.NewPeriodicReader(exporter)),
+	)
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, nil
+}
@@ -32,18 +32,30 @@ const _ = twirp.TwirpPackageMinVersion_8_1_0
 // =====================
 
 type ChatService interface {
-	// create a new conversation by sending a message and getting a reply
+	// Create a new conversation by sending a message and getting a reply
 	// use ContinueConversation with the returned conversation_id to continue the conversation
 	StartConversation(context.Context, *StartConversationRequest) (*StartConversationResponse, error)
 
-	// continue an existing conversation by adding a new message and getting a reply
+	// Continue an existing conversation by adding a new message and getting a reply
 	ContinueConversation(context.Context, *ContinueConversationRequest) (*ContinueConversationResponse, error)
 
-	// list most recent conversations
+	// List most recent conversations
 	ListConversations(context.Context, *ListConversationsRequest) (*ListConversationsResponse, error)
 
-	// describe a conversation by its ID
+	// Describe a conversation by its ID
 	DescribeConversation(context.Context, *DescribeConversationRequest) (*DescribeConversationResponse, error)
+
+	// Delete a conversation and all its messages by its ID
+	DeleteConversation(context.Context, *DeleteConversationRequest) (*DeleteConversationResponse, error)
+
+	// Search conversations by title and message content, best matches first
+	SearchConversations(context.Context, *SearchConversationsRequest) (*SearchConversationsResponse, error)
+
+	// List the messages of a conversation, oldest first
+	ListMessages(context.Context, *ListMessagesRequest) (*ListMessagesResponse, error)
+
+	// Pin or unpin a message, so that it is always part of the context sent to the model
+	PinMessage(context.Context, *PinMessageRequest) (*PinMessageResponse, error)
 }
 
 // ===========================
@@ -52,7 +64,7 @@ type ChatService interface {
 
 type chatServiceProtobufClient struct {
 	client      HTTPClient
-	urls        [4]string
+	urls        [8]string
 	interceptor twirp.Interceptor
 	opts        twirp.ClientOptions
 }
@@ -80,11 +92,15 @@ func NewChatServiceProtobufClient(baseURL string, client HTTPClient, opts ...twi
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
 	serviceURL := sanitizeBaseURL(baseURL)
 	serviceURL += baseServicePath(pathPrefix, "acai.chat", "ChatService")
-	urls := [4]string{
+	urls := [8]string{
 		serviceURL + "StartConversation",
 		serviceURL + "ContinueConversation",
 		serviceURL + "ListConversations",
 		serviceURL + "DescribeConversation",
+		serviceURL + "DeleteConversation",
+		serviceURL + "SearchConversations",
+		serviceURL + "ListMessages",
+		serviceURL + "PinMessage",
 	}
 
 	return &chatServiceProtobufClient{
@@ -279,13 +295,197 @@ func (c *chatServiceProtobufClient) callDescribeConversation(ctx context.Context
 	return out, nil
 }
 
+func (c *chatServiceProtobufClient) DeleteConversation(ctx context.Context, in *DeleteConversationRequest) (*DeleteConversationResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteConversation")
+	caller := c.callDeleteConversation
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *DeleteConversationRequest) (*DeleteConversationResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*DeleteConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*DeleteConversationRequest) when calling interceptor")
+					}
+					return c.callDeleteConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*DeleteConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*DeleteConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceProtobufClient) callDeleteConversation(ctx context.Context, in *DeleteConversationRequest) (*DeleteConversationResponse, error) {
+	out := new(DeleteConversationResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[4], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceProtobufClient) SearchConversations(ctx context.Context, in *SearchConversationsRequest) (*SearchConversationsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "SearchConversations")
+	caller := c.callSearchConversations
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SearchConversationsRequest) (*SearchConversationsResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SearchConversationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SearchConversationsRequest) when calling interceptor")
+					}
+					return c.callSearchConversations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SearchConversationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SearchConversationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceProtobufClient) callSearchConversations(ctx context.Context, in *SearchConversationsRequest) (*SearchConversationsResponse, error) {
+	out := new(SearchConversationsResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[5], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceProtobufClient) ListMessages(ctx context.Context, in *ListMessagesRequest) (*ListMessagesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "ListMessages")
+	caller := c.callListMessages
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ListMessagesRequest) (*ListMessagesResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListMessagesRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListMessagesRequest) when calling interceptor")
+					}
+					return c.callListMessages(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListMessagesResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListMessagesResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceProtobufClient) callListMessages(ctx context.Context, in *ListMessagesRequest) (*ListMessagesResponse, error) {
+	out := new(ListMessagesResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[6], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceProtobufClient) PinMessage(ctx context.Context, in *PinMessageRequest) (*PinMessageResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "PinMessage")
+	caller := c.callPinMessage
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *PinMessageRequest) (*PinMessageResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*PinMessageRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*PinMessageRequest) when calling interceptor")
+					}
+					return c.callPinMessage(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*PinMessageResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*PinMessageResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceProtobufClient) callPinMessage(ctx context.Context, in *PinMessageRequest) (*PinMessageResponse, error) {
+	out := new(PinMessageResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[7], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
 // =======================
 // ChatService JSON Client
 // =======================
 
 type chatServiceJSONClient struct {
 	client      HTTPClient
-	urls        [4]string
+	urls        [8]string
 	interceptor twirp.Interceptor
 	opts        twirp.ClientOptions
 }
@@ -313,11 +513,15 @@ func NewChatServiceJSONClient(baseURL string, client HTTPClient, opts ...twirp.C
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
 	serviceURL := sanitizeBaseURL(baseURL)
 	serviceURL += baseServicePath(pathPrefix, "acai.chat", "ChatService")
-	urls := [4]string{
+	urls := [8]string{
 		serviceURL + "StartConversation",
 		serviceURL + "ContinueConversation",
 		serviceURL + "ListConversations",
 		serviceURL + "DescribeConversation",
+		serviceURL + "DeleteConversation",
+		serviceURL + "SearchConversations",
+		serviceURL + "ListMessages",
+		serviceURL + "PinMessage",
 	}
 
 	return &chatServiceJSONClient{
@@ -479,7 +683,1022 @@ func (c *chatServiceJSONClient) DescribeConversation(ctx context.Context, in *De
 					if !ok {
 						return nil, twirp.InternalError("failed type assertion req.(*DescribeConversationRequest) when calling interceptor")
 					}
-					return c.callDescribeConversation(ctx, typedReq)
+					return c.callDescribeConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*DescribeConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*DescribeConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceJSONClient) callDescribeConversation(ctx context.Context, in *DescribeConversationRequest) (*DescribeConversationResponse, error) {
+	out := new(DescribeConversationResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[3], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceJSONClient) DeleteConversation(ctx context.Context, in *DeleteConversationRequest) (*DeleteConversationResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteConversation")
+	caller := c.callDeleteConversation
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *DeleteConversationRequest) (*DeleteConversationResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*DeleteConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*DeleteConversationRequest) when calling interceptor")
+					}
+					return c.callDeleteConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*DeleteConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*DeleteConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceJSONClient) callDeleteConversation(ctx context.Context, in *DeleteConversationRequest) (*DeleteConversationResponse, error) {
+	out := new(DeleteConversationResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[4], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceJSONClient) SearchConversations(ctx context.Context, in *SearchConversationsRequest) (*SearchConversationsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "SearchConversations")
+	caller := c.callSearchConversations
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SearchConversationsRequest) (*SearchConversationsResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SearchConversationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SearchConversationsRequest) when calling interceptor")
+					}
+					return c.callSearchConversations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SearchConversationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SearchConversationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceJSONClient) callSearchConversations(ctx context.Context, in *SearchConversationsRequest) (*SearchConversationsResponse, error) {
+	out := new(SearchConversationsResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[5], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceJSONClient) ListMessages(ctx context.Context, in *ListMessagesRequest) (*ListMessagesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "ListMessages")
+	caller := c.callListMessages
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ListMessagesRequest) (*ListMessagesResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListMessagesRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListMessagesRequest) when calling interceptor")
+					}
+					return c.callListMessages(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListMessagesResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListMessagesResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceJSONClient) callListMessages(ctx context.Context, in *ListMessagesRequest) (*ListMessagesResponse, error) {
+	out := new(ListMessagesResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[6], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *chatServiceJSONClient) PinMessage(ctx context.Context, in *PinMessageRequest) (*PinMessageResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithMethodName(ctx, "PinMessage")
+	caller := c.callPinMessage
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *PinMessageRequest) (*PinMessageResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*PinMessageRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*PinMessageRequest) when calling interceptor")
+					}
+					return c.callPinMessage(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*PinMessageResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*PinMessageResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *chatServiceJSONClient) callPinMessage(ctx context.Context, in *PinMessageRequest) (*PinMessageResponse, error) {
+	out := new(PinMessageResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[7], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ==========================
+// ChatService Server Handler
+// ==========================
+
+type chatServiceServer struct {
+	ChatService
+	interceptor      twirp.Interceptor
+	hooks            *twirp.ServerHooks
+	pathPrefix       string // prefix for routing
+	jsonSkipDefaults bool   // do not include unpopulated fields (default values) in the response
+	jsonCamelCase    bool   // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names
+}
+
+// NewChatServiceServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewChatServiceServer(svc ChatService, opts ...interface{}) TwirpServer {
+	serverOpts := newServerOpts(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	jsonSkipDefaults := false
+	_ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)
+	jsonCamelCase := false
+	_ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &chatServiceServer{
+		ChatService:      svc,
+		hooks:            serverOpts.Hooks,
+		interceptor:      twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:       pathPrefix,
+		jsonSkipDefaults: jsonSkipDefaults,
+		jsonCamelCase:    jsonCamelCase,
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *chatServiceServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	writeError(ctx, resp, err, s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *chatServiceServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(malformedRequestError(msg), err))
+}
+
+// ChatServicePathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const ChatServicePathPrefix = "/twirp/acai.chat.ChatService/"
+
+func (s *chatServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
+	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+
+	var err error
+	ctx, err = callRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" {
+		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := parseTwirpPath(req.URL.Path)
+	if pkgService != "acai.chat.ChatService" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "StartConversation":
+		s.serveStartConversation(ctx, resp, req)
+		return
+	case "ContinueConversation":
+		s.serveContinueConversation(ctx, resp, req)
+		return
+	case "ListConversations":
+		s.serveListConversations(ctx, resp, req)
+		return
+	case "DescribeConversation":
+		s.serveDescribeConversation(ctx, resp, req)
+		return
+	case "DeleteConversation":
+		s.serveDeleteConversation(ctx, resp, req)
+		return
+	case "SearchConversations":
+		s.serveSearchConversations(ctx, resp, req)
+		return
+	case "ListMessages":
+		s.serveListMessages(ctx, resp, req)
+		return
+	case "PinMessage":
+		s.servePinMessage(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *chatServiceServer) serveStartConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveStartConversationJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveStartConversationProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *chatServiceServer) serveStartConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "StartConversation")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(StartConversationRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.ChatService.StartConversation
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *StartConversationRequest) (*StartConversationResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*StartConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*StartConversationRequest) when calling interceptor")
+					}
+					return s.ChatService.StartConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*StartConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*StartConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *StartConversationResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *StartConversationResponse and nil error while calling StartConversation. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveStartConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "StartConversation")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(StartConversationRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.ChatService.StartConversation
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *StartConversationRequest) (*StartConversationResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*StartConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*StartConversationRequest) when calling interceptor")
+					}
+					return s.ChatService.StartConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*StartConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*StartConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *StartConversationResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *StartConversationResponse and nil error while calling StartConversation. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveContinueConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveContinueConversationJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveContinueConversationProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *chatServiceServer) serveContinueConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ContinueConversation")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(ContinueConversationRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.ChatService.ContinueConversation
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ContinueConversationRequest) (*ContinueConversationResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ContinueConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ContinueConversationRequest) when calling interceptor")
+					}
+					return s.ChatService.ContinueConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ContinueConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ContinueConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ContinueConversationResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ContinueConversationResponse and nil error while calling ContinueConversation. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveContinueConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ContinueConversation")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(ContinueConversationRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.ChatService.ContinueConversation
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ContinueConversationRequest) (*ContinueConversationResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ContinueConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ContinueConversationRequest) when calling interceptor")
+					}
+					return s.ChatService.ContinueConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ContinueConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ContinueConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ContinueConversationResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ContinueConversationResponse and nil error while calling ContinueConversation. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveListConversations(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListConversationsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListConversationsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *chatServiceServer) serveListConversationsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListConversations")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(ListConversationsRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.ChatService.ListConversations
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ListConversationsRequest) (*ListConversationsResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListConversationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListConversationsRequest) when calling interceptor")
+					}
+					return s.ChatService.ListConversations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListConversationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListConversationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ListConversationsResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListConversationsResponse and nil error while calling ListConversations. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveListConversationsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListConversations")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(ListConversationsRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.ChatService.ListConversations
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ListConversationsRequest) (*ListConversationsResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListConversationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListConversationsRequest) when calling interceptor")
+					}
+					return s.ChatService.ListConversations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListConversationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListConversationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ListConversationsResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListConversationsResponse and nil error while calling ListConversations. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveDescribeConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveDescribeConversationJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveDescribeConversationProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *chatServiceServer) serveDescribeConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DescribeConversation")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(DescribeConversationRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.ChatService.DescribeConversation
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *DescribeConversationRequest) (*DescribeConversationResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*DescribeConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*DescribeConversationRequest) when calling interceptor")
+					}
+					return s.ChatService.DescribeConversation(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*DescribeConversationResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*DescribeConversationResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *DescribeConversationResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DescribeConversationResponse and nil error while calling DescribeConversation. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *chatServiceServer) serveDescribeConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DescribeConversation")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(DescribeConversationRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.ChatService.DescribeConversation
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *DescribeConversationRequest) (*DescribeConversationResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*DescribeConversationRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*DescribeConversationRequest) when calling interceptor")
+					}
+					return s.ChatService.DescribeConversation(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
@@ -492,143 +1711,44 @@ func (c *chatServiceJSONClient) DescribeConversation(ctx context.Context, in *De
 			return nil, err
 		}
 	}
-	return caller(ctx, in)
-}
-
-func (c *chatServiceJSONClient) callDescribeConversation(ctx context.Context, in *DescribeConversationRequest) (*DescribeConversationResponse, error) {
-	out := new(DescribeConversationResponse)
-	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[3], in, out)
-	if err != nil {
-		twerr, ok := err.(twirp.Error)
-		if !ok {
-			twerr = twirp.InternalErrorWith(err)
-		}
-		callClientError(ctx, c.opts.Hooks, twerr)
-		return nil, err
-	}
-
-	callClientResponseReceived(ctx, c.opts.Hooks)
-
-	return out, nil
-}
-
-// ==========================
-// ChatService Server Handler
-// ==========================
-
-type chatServiceServer struct {
-	ChatService
-	interceptor      twirp.Interceptor
-	hooks            *twirp.ServerHooks
-	pathPrefix       string // prefix for routing
-	jsonSkipDefaults bool   // do not include unpopulated fields (default values) in the response
-	jsonCamelCase    bool   // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names
-}
-
-// NewChatServiceServer builds a TwirpServer that can be used as an http.Handler to handle
-// HTTP requests that are routed to the right method in the provided svc implementation.
-// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
-func NewChatServiceServer(svc ChatService, opts ...interface{}) TwirpServer {
-	serverOpts := newServerOpts(opts)
-
-	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
-	jsonSkipDefaults := false
-	_ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)
-	jsonCamelCase := false
-	_ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)
-	var pathPrefix string
-	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
-		pathPrefix = "/twirp" // default prefix
-	}
-
-	return &chatServiceServer{
-		ChatService:      svc,
-		hooks:            serverOpts.Hooks,
-		interceptor:      twirp.ChainInterceptors(serverOpts.Interceptors...),
-		pathPrefix:       pathPrefix,
-		jsonSkipDefaults: jsonSkipDefaults,
-		jsonCamelCase:    jsonCamelCase,
-	}
-}
-
-// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
-// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
-func (s *chatServiceServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
-	writeError(ctx, resp, err, s.hooks)
-}
-
-// handleRequestBodyError is used to handle error when the twirp server cannot read request
-func (s *chatServiceServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
-	if context.Canceled == ctx.Err() {
-		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
-		return
-	}
-	if context.DeadlineExceeded == ctx.Err() {
-		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
-		return
-	}
-	s.writeError(ctx, resp, twirp.WrapError(malformedRequestError(msg), err))
-}
-
-// ChatServicePathPrefix is a convenience constant that may identify URL paths.
-// Should be used with caution, it only matches routes generated by Twirp Go clients,
-// with the default "/twirp" prefix and default CamelCase service and method names.
-// More info: https://twitchtv.github.io/twirp/docs/routing.html
-const ChatServicePathPrefix = "/twirp/acai.chat.ChatService/"
 
-func (s *chatServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
-	ctx := req.Context()
-	ctx = ctxsetters.WithPackageName(ctx, "acai.chat")
-	ctx = ctxsetters.WithServiceName(ctx, "ChatService")
-	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	// Call service method
+	var respContent *DescribeConversationResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
 
-	var err error
-	ctx, err = callRequestReceived(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
 	}
-
-	if req.Method != "POST" {
-		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DescribeConversationResponse and nil error while calling DescribeConversation. nil responses are not supported"))
 		return
 	}
 
-	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
-	prefix, pkgService, method := parseTwirpPath(req.URL.Path)
-	if pkgService != "acai.chat.ChatService" {
-		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
-		return
-	}
-	if prefix != s.pathPrefix {
-		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
 		return
 	}
 
-	switch method {
-	case "StartConversation":
-		s.serveStartConversation(ctx, resp, req)
-		return
-	case "ContinueConversation":
-		s.serveContinueConversation(ctx, resp, req)
-		return
-	case "ListConversations":
-		s.serveListConversations(ctx, resp, req)
-		return
-	case "DescribeConversation":
-		s.serveDescribeConversation(ctx, resp, req)
-		return
-	default:
-		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
-		return
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
 	}
+	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveStartConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveDeleteConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
 	if i == -1 {
@@ -636,9 +1756,9 @@ func (s *chatServiceServer) serveStartConversation(ctx context.Context, resp htt
 	}
 	switch strings.TrimSpace(strings.ToLower(header[:i])) {
 	case "application/json":
-		s.serveStartConversationJSON(ctx, resp, req)
+		s.serveDeleteConversationJSON(ctx, resp, req)
 	case "application/protobuf":
-		s.serveStartConversationProtobuf(ctx, resp, req)
+		s.serveDeleteConversationProtobuf(ctx, resp, req)
 	default:
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
 		twerr := badRouteError(msg, req.Method, req.URL.Path)
@@ -646,9 +1766,9 @@ func (s *chatServiceServer) serveStartConversation(ctx context.Context, resp htt
 	}
 }
 
-func (s *chatServiceServer) serveStartConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveDeleteConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "StartConversation")
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteConversation")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -661,29 +1781,29 @@ func (s *chatServiceServer) serveStartConversationJSON(ctx context.Context, resp
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
-	reqContent := new(StartConversationRequest)
+	reqContent := new(DeleteConversationRequest)
 	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
 	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
 
-	handler := s.ChatService.StartConversation
+	handler := s.ChatService.DeleteConversation
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *StartConversationRequest) (*StartConversationResponse, error) {
+		handler = func(ctx context.Context, req *DeleteConversationRequest) (*DeleteConversationResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*StartConversationRequest)
+					typedReq, ok := req.(*DeleteConversationRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*StartConversationRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*DeleteConversationRequest) when calling interceptor")
 					}
-					return s.ChatService.StartConversation(ctx, typedReq)
+					return s.ChatService.DeleteConversation(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*StartConversationResponse)
+				typedResp, ok := resp.(*DeleteConversationResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*StartConversationResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*DeleteConversationResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -692,7 +1812,7 @@ func (s *chatServiceServer) serveStartConversationJSON(ctx context.Context, resp
 	}
 
 	// Call service method
-	var respContent *StartConversationResponse
+	var respContent *DeleteConversationResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -703,7 +1823,7 @@ func (s *chatServiceServer) serveStartConversationJSON(ctx context.Context, resp
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *StartConversationResponse and nil error while calling StartConversation. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DeleteConversationResponse and nil error while calling DeleteConversation. nil responses are not supported"))
 		return
 	}
 
@@ -729,9 +1849,9 @@ func (s *chatServiceServer) serveStartConversationJSON(ctx context.Context, resp
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveStartConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveDeleteConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "StartConversation")
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteConversation")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -743,28 +1863,28 @@ func (s *chatServiceServer) serveStartConversationProtobuf(ctx context.Context,
 		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	reqContent := new(StartConversationRequest)
+	reqContent := new(DeleteConversationRequest)
 	if err = proto.Unmarshal(buf, reqContent); err != nil {
 		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
 
-	handler := s.ChatService.StartConversation
+	handler := s.ChatService.DeleteConversation
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *StartConversationRequest) (*StartConversationResponse, error) {
+		handler = func(ctx context.Context, req *DeleteConversationRequest) (*DeleteConversationResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*StartConversationRequest)
+					typedReq, ok := req.(*DeleteConversationRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*StartConversationRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*DeleteConversationRequest) when calling interceptor")
 					}
-					return s.ChatService.StartConversation(ctx, typedReq)
+					return s.ChatService.DeleteConversation(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*StartConversationResponse)
+				typedResp, ok := resp.(*DeleteConversationResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*StartConversationResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*DeleteConversationResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -773,7 +1893,7 @@ func (s *chatServiceServer) serveStartConversationProtobuf(ctx context.Context,
 	}
 
 	// Call service method
-	var respContent *StartConversationResponse
+	var respContent *DeleteConversationResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -784,7 +1904,7 @@ func (s *chatServiceServer) serveStartConversationProtobuf(ctx context.Context,
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *StartConversationResponse and nil error while calling StartConversation. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DeleteConversationResponse and nil error while calling DeleteConversation. nil responses are not supported"))
 		return
 	}
 
@@ -808,7 +1928,7 @@ func (s *chatServiceServer) serveStartConversationProtobuf(ctx context.Context,
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveContinueConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveSearchConversations(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
 	if i == -1 {
@@ -816,9 +1936,9 @@ func (s *chatServiceServer) serveContinueConversation(ctx context.Context, resp
 	}
 	switch strings.TrimSpace(strings.ToLower(header[:i])) {
 	case "application/json":
-		s.serveContinueConversationJSON(ctx, resp, req)
+		s.serveSearchConversationsJSON(ctx, resp, req)
 	case "application/protobuf":
-		s.serveContinueConversationProtobuf(ctx, resp, req)
+		s.serveSearchConversationsProtobuf(ctx, resp, req)
 	default:
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
 		twerr := badRouteError(msg, req.Method, req.URL.Path)
@@ -826,9 +1946,9 @@ func (s *chatServiceServer) serveContinueConversation(ctx context.Context, resp
 	}
 }
 
-func (s *chatServiceServer) serveContinueConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveSearchConversationsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "ContinueConversation")
+	ctx = ctxsetters.WithMethodName(ctx, "SearchConversations")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -841,29 +1961,29 @@ func (s *chatServiceServer) serveContinueConversationJSON(ctx context.Context, r
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
-	reqContent := new(ContinueConversationRequest)
+	reqContent := new(SearchConversationsRequest)
 	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
 	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
 
-	handler := s.ChatService.ContinueConversation
+	handler := s.ChatService.SearchConversations
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *ContinueConversationRequest) (*ContinueConversationResponse, error) {
+		handler = func(ctx context.Context, req *SearchConversationsRequest) (*SearchConversationsResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ContinueConversationRequest)
+					typedReq, ok := req.(*SearchConversationsRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ContinueConversationRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*SearchConversationsRequest) when calling interceptor")
 					}
-					return s.ChatService.ContinueConversation(ctx, typedReq)
+					return s.ChatService.SearchConversations(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ContinueConversationResponse)
+				typedResp, ok := resp.(*SearchConversationsResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ContinueConversationResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*SearchConversationsResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -872,7 +1992,7 @@ func (s *chatServiceServer) serveContinueConversationJSON(ctx context.Context, r
 	}
 
 	// Call service method
-	var respContent *ContinueConversationResponse
+	var respContent *SearchConversationsResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -883,7 +2003,7 @@ func (s *chatServiceServer) serveContinueConversationJSON(ctx context.Context, r
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *ContinueConversationResponse and nil error while calling ContinueConversation. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SearchConversationsResponse and nil error while calling SearchConversations. nil responses are not supported"))
 		return
 	}
 
@@ -909,9 +2029,9 @@ func (s *chatServiceServer) serveContinueConversationJSON(ctx context.Context, r
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveContinueConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveSearchConversationsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "ContinueConversation")
+	ctx = ctxsetters.WithMethodName(ctx, "SearchConversations")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -923,28 +2043,28 @@ func (s *chatServiceServer) serveContinueConversationProtobuf(ctx context.Contex
 		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	reqContent := new(ContinueConversationRequest)
+	reqContent := new(SearchConversationsRequest)
 	if err = proto.Unmarshal(buf, reqContent); err != nil {
 		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
 
-	handler := s.ChatService.ContinueConversation
+	handler := s.ChatService.SearchConversations
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *ContinueConversationRequest) (*ContinueConversationResponse, error) {
+		handler = func(ctx context.Context, req *SearchConversationsRequest) (*SearchConversationsResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ContinueConversationRequest)
+					typedReq, ok := req.(*SearchConversationsRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ContinueConversationRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*SearchConversationsRequest) when calling interceptor")
 					}
-					return s.ChatService.ContinueConversation(ctx, typedReq)
+					return s.ChatService.SearchConversations(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ContinueConversationResponse)
+				typedResp, ok := resp.(*SearchConversationsResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ContinueConversationResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*SearchConversationsResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -953,7 +2073,7 @@ func (s *chatServiceServer) serveContinueConversationProtobuf(ctx context.Contex
 	}
 
 	// Call service method
-	var respContent *ContinueConversationResponse
+	var respContent *SearchConversationsResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -964,7 +2084,7 @@ func (s *chatServiceServer) serveContinueConversationProtobuf(ctx context.Contex
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *ContinueConversationResponse and nil error while calling ContinueConversation. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SearchConversationsResponse and nil error while calling SearchConversations. nil responses are not supported"))
 		return
 	}
 
@@ -988,7 +2108,7 @@ func (s *chatServiceServer) serveContinueConversationProtobuf(ctx context.Contex
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveListConversations(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveListMessages(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
 	if i == -1 {
@@ -996,9 +2116,9 @@ func (s *chatServiceServer) serveListConversations(ctx context.Context, resp htt
 	}
 	switch strings.TrimSpace(strings.ToLower(header[:i])) {
 	case "application/json":
-		s.serveListConversationsJSON(ctx, resp, req)
+		s.serveListMessagesJSON(ctx, resp, req)
 	case "application/protobuf":
-		s.serveListConversationsProtobuf(ctx, resp, req)
+		s.serveListMessagesProtobuf(ctx, resp, req)
 	default:
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
 		twerr := badRouteError(msg, req.Method, req.URL.Path)
@@ -1006,9 +2126,9 @@ func (s *chatServiceServer) serveListConversations(ctx context.Context, resp htt
 	}
 }
 
-func (s *chatServiceServer) serveListConversationsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveListMessagesJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "ListConversations")
+	ctx = ctxsetters.WithMethodName(ctx, "ListMessages")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -1021,29 +2141,29 @@ func (s *chatServiceServer) serveListConversationsJSON(ctx context.Context, resp
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
-	reqContent := new(ListConversationsRequest)
+	reqContent := new(ListMessagesRequest)
 	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
 	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
 
-	handler := s.ChatService.ListConversations
+	handler := s.ChatService.ListMessages
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *ListConversationsRequest) (*ListConversationsResponse, error) {
+		handler = func(ctx context.Context, req *ListMessagesRequest) (*ListMessagesResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ListConversationsRequest)
+					typedReq, ok := req.(*ListMessagesRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ListConversationsRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*ListMessagesRequest) when calling interceptor")
 					}
-					return s.ChatService.ListConversations(ctx, typedReq)
+					return s.ChatService.ListMessages(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ListConversationsResponse)
+				typedResp, ok := resp.(*ListMessagesResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ListConversationsResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*ListMessagesResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -1052,7 +2172,7 @@ func (s *chatServiceServer) serveListConversationsJSON(ctx context.Context, resp
 	}
 
 	// Call service method
-	var respContent *ListConversationsResponse
+	var respContent *ListMessagesResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -1063,7 +2183,7 @@ func (s *chatServiceServer) serveListConversationsJSON(ctx context.Context, resp
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListConversationsResponse and nil error while calling ListConversations. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListMessagesResponse and nil error while calling ListMessages. nil responses are not supported"))
 		return
 	}
 
@@ -1089,9 +2209,9 @@ func (s *chatServiceServer) serveListConversationsJSON(ctx context.Context, resp
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveListConversationsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) serveListMessagesProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "ListConversations")
+	ctx = ctxsetters.WithMethodName(ctx, "ListMessages")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -1103,28 +2223,28 @@ func (s *chatServiceServer) serveListConversationsProtobuf(ctx context.Context,
 		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	reqContent := new(ListConversationsRequest)
+	reqContent := new(ListMessagesRequest)
 	if err = proto.Unmarshal(buf, reqContent); err != nil {
 		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
 
-	handler := s.ChatService.ListConversations
+	handler := s.ChatService.ListMessages
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *ListConversationsRequest) (*ListConversationsResponse, error) {
+		handler = func(ctx context.Context, req *ListMessagesRequest) (*ListMessagesResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ListConversationsRequest)
+					typedReq, ok := req.(*ListMessagesRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ListConversationsRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*ListMessagesRequest) when calling interceptor")
 					}
-					return s.ChatService.ListConversations(ctx, typedReq)
+					return s.ChatService.ListMessages(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ListConversationsResponse)
+				typedResp, ok := resp.(*ListMessagesResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ListConversationsResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*ListMessagesResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -1133,7 +2253,7 @@ func (s *chatServiceServer) serveListConversationsProtobuf(ctx context.Context,
 	}
 
 	// Call service method
-	var respContent *ListConversationsResponse
+	var respContent *ListMessagesResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -1144,7 +2264,7 @@ func (s *chatServiceServer) serveListConversationsProtobuf(ctx context.Context,
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListConversationsResponse and nil error while calling ListConversations. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListMessagesResponse and nil error while calling ListMessages. nil responses are not supported"))
 		return
 	}
 
@@ -1168,7 +2288,7 @@ func (s *chatServiceServer) serveListConversationsProtobuf(ctx context.Context,
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveDescribeConversation(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) servePinMessage(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
 	if i == -1 {
@@ -1176,9 +2296,9 @@ func (s *chatServiceServer) serveDescribeConversation(ctx context.Context, resp
 	}
 	switch strings.TrimSpace(strings.ToLower(header[:i])) {
 	case "application/json":
-		s.serveDescribeConversationJSON(ctx, resp, req)
+		s.servePinMessageJSON(ctx, resp, req)
 	case "application/protobuf":
-		s.serveDescribeConversationProtobuf(ctx, resp, req)
+		s.servePinMessageProtobuf(ctx, resp, req)
 	default:
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
 		twerr := badRouteError(msg, req.Method, req.URL.Path)
@@ -1186,9 +2306,9 @@ func (s *chatServiceServer) serveDescribeConversation(ctx context.Context, resp
 	}
 }
 
-func (s *chatServiceServer) serveDescribeConversationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) servePinMessageJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "DescribeConversation")
+	ctx = ctxsetters.WithMethodName(ctx, "PinMessage")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -1201,29 +2321,29 @@ func (s *chatServiceServer) serveDescribeConversationJSON(ctx context.Context, r
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
-	reqContent := new(DescribeConversationRequest)
+	reqContent := new(PinMessageRequest)
 	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
 	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
 
-	handler := s.ChatService.DescribeConversation
+	handler := s.ChatService.PinMessage
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *DescribeConversationRequest) (*DescribeConversationResponse, error) {
+		handler = func(ctx context.Context, req *PinMessageRequest) (*PinMessageResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*DescribeConversationRequest)
+					typedReq, ok := req.(*PinMessageRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*DescribeConversationRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*PinMessageRequest) when calling interceptor")
 					}
-					return s.ChatService.DescribeConversation(ctx, typedReq)
+					return s.ChatService.PinMessage(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*DescribeConversationResponse)
+				typedResp, ok := resp.(*PinMessageResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*DescribeConversationResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*PinMessageResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -1232,7 +2352,7 @@ func (s *chatServiceServer) serveDescribeConversationJSON(ctx context.Context, r
 	}
 
 	// Call service method
-	var respContent *DescribeConversationResponse
+	var respContent *PinMessageResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -1243,7 +2363,7 @@ func (s *chatServiceServer) serveDescribeConversationJSON(ctx context.Context, r
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *DescribeConversationResponse and nil error while calling DescribeConversation. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *PinMessageResponse and nil error while calling PinMessage. nil responses are not supported"))
 		return
 	}
 
@@ -1269,9 +2389,9 @@ func (s *chatServiceServer) serveDescribeConversationJSON(ctx context.Context, r
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *chatServiceServer) serveDescribeConversationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *chatServiceServer) servePinMessageProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "DescribeConversation")
+	ctx = ctxsetters.WithMethodName(ctx, "PinMessage")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -1283,28 +2403,28 @@ func (s *chatServiceServer) serveDescribeConversationProtobuf(ctx context.Contex
 		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	reqContent := new(DescribeConversationRequest)
+	reqContent := new(PinMessageRequest)
 	if err = proto.Unmarshal(buf, reqContent); err != nil {
 		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
 
-	handler := s.ChatService.DescribeConversation
+	handler := s.ChatService.PinMessage
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *DescribeConversationRequest) (*DescribeConversationResponse, error) {
+		handler = func(ctx context.Context, req *PinMessageRequest) (*PinMessageResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*DescribeConversationRequest)
+					typedReq, ok := req.(*PinMessageRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*DescribeConversationRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*PinMessageRequest) when calling interceptor")
 					}
-					return s.ChatService.DescribeConversation(ctx, typedReq)
+					return s.ChatService.PinMessage(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*DescribeConversationResponse)
+				typedResp, ok := resp.(*PinMessageResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*DescribeConversationResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*PinMessageResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -1313,7 +2433,7 @@ func (s *chatServiceServer) serveDescribeConversationProtobuf(ctx context.Contex
 	}
 
 	// Call service method
-	var respContent *DescribeConversationResponse
+	var respContent *PinMessageResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -1324,7 +2444,7 @@ func (s *chatServiceServer) serveDescribeConversationProtobuf(ctx context.Contex
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *DescribeConversationResponse and nil error while calling DescribeConversation. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *PinMessageResponse and nil error while calling PinMessage. nil responses are not supported"))
 		return
 	}
 
@@ -1929,39 +3049,77 @@ func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 529 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x94, 0x51, 0x6b, 0xd3, 0x50,
-	0x14, 0xc7, 0x4d, 0xda, 0xd9, 0xe5, 0x64, 0xad, 0xdd, 0xa5, 0x60, 0x96, 0x15, 0x56, 0xa2, 0xb8,
-	0x3e, 0x48, 0x2a, 0x75, 0x0f, 0xc2, 0xf0, 0x61, 0x56, 0x85, 0xa1, 0x56, 0x48, 0x3a, 0x84, 0x09,
-	0xd3, 0x34, 0xbb, 0x66, 0x17, 0xd2, 0xdc, 0x98, 0x7b, 0x3b, 0xf0, 0xd3, 0xec, 0x83, 0xfa, 0x22,
-	0x4d, 0x6e, 0xe3, 0xbd, 0x34, 0xa9, 0x8a, 0x8f, 0xe7, 0xe4, 0x7f, 0xcf, 0xf9, 0xfd, 0xcf, 0x39,
-	0x04, 0x3a, 0x59, 0x1a, 0x8e, 0xc2, 0x9b, 0x80, 0xbb, 0x69, 0x46, 0x39, 0x45, 0x46, 0x10, 0x06,
-	0xc4, 0x5d, 0x25, 0xec, 0xa3, 0x88, 0xd2, 0x28, 0xc6, 0xa3, 0xfc, 0xc3, 0x7c, 0xf9, 0x6d, 0xc4,
-	0xc9, 0x02, 0x33, 0x1e, 0x2c, 0xd2, 0x42, 0xeb, 0xfc, 0xd4, 0x61, 0x6f, 0x42, 0x93, 0x5b, 0x9c,
-	0xb1, 0x80, 0x13, 0x9a, 0xa0, 0x0e, 0xe8, 0xe4, 0xda, 0xd2, 0x06, 0xda, 0xd0, 0xf0, 0x74, 0x72,
-	0x8d, 0x7a, 0xb0, 0xc3, 0x09, 0x8f, 0xb1, 0xa5, 0xe7, 0xa9, 0x22, 0x40, 0x2f, 0xc0, 0x28, 0x2b,
-	0x59, 0x8d, 0x81, 0x36, 0x34, 0xc7, 0xb6, 0x5b, 0xf4, 0x72, 0xd7, 0xbd, 0xdc, 0xd9, 0x5a, 0xe1,
-	0xfd, 0x16, 0xa3, 0x53, 0xd8, 0x5d, 0x60, 0xc6, 0x82, 0x08, 0x33, 0xab, 0x39, 0x68, 0x0c, 0xcd,
-	0xf1, 0x91, 0x5b, 0xf2, 0xba, 0x32, 0x8a, 0xfb, 0xa1, 0xd0, 0x79, 0xe5, 0x03, 0xfb, 0x4e, 0x83,
-	0x96, 0xc8, 0x6e, 0x80, 0x3e, 0x83, 0x66, 0x46, 0x05, 0x67, 0x67, 0xdc, 0xaf, 0x2b, 0xea, 0xd1,
-	0x18, 0x7b, 0xb9, 0x12, 0x59, 0xd0, 0x0a, 0x69, 0xc2, 0x71, 0xc2, 0x73, 0x0b, 0x86, 0xb7, 0x0e,
-	0x55, 0x7b, 0xcd, 0x7f, 0xb0, 0xe7, 0x3c, 0x85, 0xe6, 0xaa, 0x03, 0x32, 0xa1, 0x75, 0x31, 0x7d,
-	0x37, 0xfd, 0xf8, 0x69, 0xda, 0xbd, 0x87, 0x76, 0xa1, 0x79, 0xe1, 0xbf, 0xf1, 0xba, 0x1a, 0x6a,
-	0x83, 0x71, 0xe6, 0xfb, 0xe7, 0xfe, 0xec, 0x6c, 0x3a, 0xeb, 0xea, 0xce, 0x09, 0x58, 0x3e, 0x0f,
-	0x32, 0x2e, 0x13, 0x7a, 0xf8, 0xfb, 0x12, 0x33, 0xbe, 0xa2, 0x13, 0xbe, 0x85, 0xc9, 0x75, 0xe8,
-	0xa4, 0x70, 0x50, 0xf1, 0x8a, 0xa5, 0x34, 0x61, 0x18, 0x1d, 0xc3, 0x83, 0x50, 0xca, 0x7f, 0x29,
-	0x67, 0xd4, 0x91, 0xd3, 0xe7, 0x75, 0x8b, 0xed, 0xc1, 0x4e, 0x86, 0xd3, 0xf8, 0x87, 0x98, 0x48,
-	0x11, 0x38, 0x5f, 0xe1, 0x70, 0x42, 0x13, 0x4e, 0x92, 0x25, 0xae, 0x42, 0xfd, 0xeb, 0x9e, 0x92,
-	0x27, 0x5d, 0xf5, 0x74, 0x02, 0xfd, 0xea, 0x0e, 0xc2, 0x56, 0xc9, 0xa5, 0xc9, 0x5c, 0x36, 0x58,
-	0xef, 0x09, 0x53, 0x06, 0xc1, 0x04, 0x94, 0x73, 0x09, 0x07, 0x15, 0xdf, 0x44, 0xb9, 0x97, 0xd0,
-	0x96, 0xd1, 0x98, 0xa5, 0xe5, 0xa7, 0xf8, 0xb0, 0xe6, 0x6a, 0x3c, 0x55, 0xed, 0xbc, 0x85, 0xc3,
-	0xd7, 0x98, 0x85, 0x19, 0x99, 0xff, 0xd7, 0x3c, 0x9c, 0xcf, 0xd0, 0xaf, 0xae, 0x23, 0x30, 0x4f,
-	0x61, 0x4f, 0x7e, 0x91, 0x57, 0xd9, 0x42, 0xa9, 0x88, 0xc7, 0x77, 0x0d, 0x30, 0x27, 0x37, 0x01,
-	0xf7, 0x71, 0x76, 0x4b, 0x42, 0x8c, 0xae, 0x60, 0x7f, 0xe3, 0x6c, 0xd0, 0x23, 0xa9, 0x56, 0xdd,
-	0x29, 0xda, 0x8f, 0xb7, 0x8b, 0x04, 0x6c, 0x04, 0xbd, 0xaa, 0x15, 0xa2, 0x27, 0x2a, 0x6e, 0xdd,
-	0x15, 0xd9, 0xc7, 0x7f, 0xd4, 0x89, 0x46, 0x57, 0xb0, 0xbf, 0xb1, 0x59, 0xc5, 0x48, 0xdd, 0x4d,
-	0x28, 0x46, 0xea, 0x8f, 0x23, 0x82, 0x5e, 0xd5, 0x56, 0x14, 0x23, 0x5b, 0xd6, 0xaf, 0x18, 0xd9,
-	0xb6, 0xde, 0x57, 0xed, 0x4b, 0x93, 0x24, 0x1c, 0x67, 0x49, 0x10, 0x8f, 0xd2, 0xf9, 0xfc, 0x7e,
-	0xfe, 0x6b, 0x79, 0xfe, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x59, 0x83, 0x17, 0xf4, 0xd0, 0x05, 0x00,
-	0x00,
+	// 1140 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x5b, 0x6f, 0xe3, 0x44,
+	0x14, 0xc6, 0xb9, 0x35, 0x39, 0xb9, 0x6c, 0x3a, 0x1b, 0x81, 0xd7, 0xed, 0xd2, 0xe0, 0xdd, 0x5e,
+	0x24, 0x44, 0x8a, 0xca, 0x3e, 0x20, 0xad, 0xd0, 0xaa, 0x4d, 0x17, 0xa8, 0xe8, 0xb6, 0x2b, 0x27,
+	0x15, 0x02, 0xa4, 0x0d, 0xae, 0x33, 0x4d, 0x47, 0xd8, 0x1e, 0xaf, 0x67, 0xb2, 0x62, 0xcb, 0x13,
+	0x2b, 0xfe, 0x09, 0x3f, 0x8f, 0x67, 0xc4, 0x2b, 0x9a, 0xf1, 0xd8, 0xb1, 0x1b, 0x27, 0x6d, 0xd5,
+	0x37, 0xcf, 0x99, 0x6f, 0xce, 0xe5, 0x3b, 0x37, 0x43, 0x2b, 0x0c, 0x9c, 0x5d, 0xe7, 0xd2, 0xe6,
+	0xbd, 0x20, 0xa4, 0x9c, 0xa2, 0x9a, 0xed, 0xd8, 0xa4, 0x27, 0x04, 0xc6, 0xc6, 0x84, 0xd2, 0x89,
+	0x8b, 0x77, 0xe5, 0xc5, 0xf9, 0xf4, 0x62, 0x97, 0x13, 0x0f, 0x33, 0x6e, 0x7b, 0x41, 0x84, 0x35,
+	0xff, 0x2b, 0x43, 0xa3, 0x4f, 0xfd, 0x77, 0x38, 0x64, 0x36, 0x27, 0xd4, 0x47, 0x2d, 0x28, 0x90,
+	0xb1, 0xae, 0x75, 0xb5, 0x9d, 0x9a, 0x55, 0x20, 0x63, 0xd4, 0x81, 0x32, 0x27, 0xdc, 0xc5, 0x7a,
+	0x41, 0x8a, 0xa2, 0x03, 0xfa, 0x1a, 0x6a, 0x89, 0x26, 0xbd, 0xd8, 0xd5, 0x76, 0xea, 0x7b, 0x46,
+	0x2f, 0xb2, 0xd5, 0x8b, 0x6d, 0xf5, 0x86, 0x31, 0xc2, 0x9a, 0x81, 0xd1, 0x73, 0xa8, 0x7a, 0x98,
+	0x31, 0x7b, 0x82, 0x99, 0x5e, 0xea, 0x16, 0x77, 0xea, 0x7b, 0x1b, 0xbd, 0xc4, 0xdf, 0x5e, 0xda,
+	0x95, 0xde, 0xab, 0x08, 0x67, 0x25, 0x0f, 0x90, 0x0e, 0x2b, 0x6c, 0xea, 0x79, 0x76, 0xf8, 0x5e,
+	0x2f, 0x4b, 0x77, 0xe2, 0x23, 0xda, 0x82, 0xf2, 0x54, 0x60, 0xf4, 0x8a, 0x74, 0xa6, 0x9d, 0xd2,
+	0x79, 0x26, 0x95, 0x44, 0xd7, 0xc6, 0x31, 0x54, 0x87, 0x94, 0xba, 0x7d, 0xdb, 0x75, 0xe7, 0x42,
+	0x45, 0x50, 0xf2, 0x6d, 0x2f, 0x8e, 0x54, 0x7e, 0xa3, 0x75, 0xa8, 0xd9, 0xe1, 0x64, 0xea, 0x61,
+	0x9f, 0x33, 0x19, 0x68, 0xcd, 0x9a, 0x09, 0x8c, 0x7f, 0x0b, 0xb0, 0xa2, 0xbc, 0x9c, 0xd3, 0xf6,
+	0x25, 0x94, 0x42, 0xaa, 0x78, 0x6b, 0xed, 0xad, 0x2f, 0x0a, 0xd2, 0xa2, 0x2e, 0xb6, 0x24, 0x52,
+	0x44, 0xe7, 0x50, 0x9f, 0x63, 0x9f, 0x2b, 0x4b, 0xf1, 0x31, 0x4b, 0x77, 0xe9, 0x2e, 0x74, 0xbf,
+	0x00, 0xe0, 0x94, 0xba, 0x23, 0xc7, 0x76, 0x5d, 0xa6, 0x97, 0x25, 0xe1, 0xdd, 0x45, 0xbe, 0xc4,
+	0xcc, 0x58, 0x35, 0xae, 0xbe, 0x18, 0xea, 0x42, 0x23, 0x51, 0x30, 0x22, 0x63, 0xc9, 0x6f, 0xcd,
+	0x82, 0x18, 0x70, 0x34, 0x46, 0x6b, 0x20, 0xe1, 0x23, 0xc9, 0xdd, 0x8a, 0xbc, 0xae, 0x0a, 0xc1,
+	0x89, 0xe0, 0xef, 0x63, 0xa8, 0x04, 0xc4, 0xf7, 0xf1, 0x58, 0xaf, 0x76, 0xb5, 0x9d, 0xaa, 0xa5,
+	0x4e, 0xa2, 0xac, 0x3c, 0x3a, 0xc6, 0xae, 0x5e, 0x8b, 0xca, 0x4a, 0x1e, 0x66, 0x59, 0x84, 0xa5,
+	0x59, 0x34, 0x0f, 0xa0, 0x24, 0x78, 0x43, 0x75, 0x58, 0x39, 0x3b, 0xf9, 0xe1, 0xe4, 0xf4, 0xc7,
+	0x93, 0xf6, 0x47, 0xa8, 0x0a, 0xa5, 0xb3, 0xc1, 0x4b, 0xab, 0xad, 0xa1, 0x26, 0xd4, 0xf6, 0x07,
+	0x83, 0xa3, 0xc1, 0x70, 0xff, 0x64, 0xd8, 0x2e, 0x88, 0x8b, 0xe1, 0xe9, 0xe9, 0x71, 0xbb, 0x88,
+	0x00, 0x2a, 0x83, 0x9f, 0x06, 0xc3, 0x97, 0xaf, 0xda, 0x25, 0xf3, 0x6f, 0x0d, 0xca, 0x52, 0x29,
+	0x7a, 0x02, 0xcd, 0x20, 0xa4, 0x5e, 0xc0, 0x47, 0x9c, 0xfe, 0x86, 0x7d, 0x26, 0x93, 0x58, 0xb4,
+	0x1a, 0x91, 0x70, 0x28, 0x65, 0xe8, 0x73, 0x58, 0x75, 0xa8, 0x17, 0xb8, 0x58, 0x30, 0x15, 0x03,
+	0x0b, 0x12, 0xd8, 0x9e, 0x5d, 0x28, 0xf0, 0x67, 0x82, 0x34, 0x6e, 0xbb, 0x31, 0xae, 0x28, 0x71,
+	0x75, 0x29, 0x53, 0x90, 0x4d, 0x68, 0x61, 0xc6, 0x89, 0x67, 0x73, 0x3c, 0x1e, 0x39, 0x94, 0x71,
+	0x99, 0x57, 0xcd, 0x6a, 0x26, 0xd2, 0x3e, 0x65, 0xdc, 0x7c, 0x06, 0xfa, 0x80, 0xdb, 0x21, 0x4f,
+	0xe7, 0xc9, 0xc2, 0x6f, 0xa7, 0x98, 0x71, 0x51, 0x2f, 0xaa, 0x33, 0x54, 0xd9, 0xc5, 0x47, 0x33,
+	0x80, 0x47, 0x39, 0xaf, 0x58, 0x40, 0x7d, 0x86, 0xd1, 0x36, 0x3c, 0x70, 0x52, 0xf2, 0x51, 0x52,
+	0xb5, 0xad, 0xb4, 0xf8, 0x68, 0x51, 0xeb, 0x77, 0xa0, 0x1c, 0xe2, 0xc0, 0x7d, 0xaf, 0x6a, 0x34,
+	0x3a, 0x98, 0xbf, 0xc2, 0x5a, 0x9f, 0xfa, 0x9c, 0xf8, 0x53, 0x9c, 0xe7, 0xea, 0xad, 0x6d, 0xa6,
+	0x62, 0x2a, 0x64, 0x63, 0x7a, 0x06, 0xeb, 0xf9, 0x16, 0x54, 0x58, 0x89, 0x5f, 0x5a, 0xda, 0xaf,
+	0x3f, 0x0b, 0xa0, 0x1f, 0x13, 0x96, 0x61, 0x82, 0xc5, 0x5e, 0xad, 0x41, 0x2d, 0xb0, 0x27, 0x78,
+	0xc4, 0xc8, 0x55, 0x44, 0x61, 0xd9, 0xaa, 0x0a, 0xc1, 0x80, 0x5c, 0x61, 0xf4, 0x18, 0x40, 0x5e,
+	0xca, 0x14, 0x2a, 0x67, 0x24, 0x5c, 0x26, 0x10, 0xbd, 0x80, 0xa6, 0x13, 0x62, 0x99, 0x3d, 0xfb,
+	0x82, 0xe3, 0xf0, 0x16, 0x53, 0xb0, 0xa1, 0x1e, 0xec, 0x0b, 0x3c, 0xda, 0x87, 0x56, 0xac, 0xe0,
+	0x1c, 0x5f, 0xd0, 0x10, 0xdf, 0xa2, 0xb1, 0x63, 0x93, 0x07, 0xf2, 0x81, 0xa8, 0x21, 0x99, 0x93,
+	0x91, 0x98, 0x13, 0x36, 0xf1, 0x99, 0x9a, 0x8a, 0x4d, 0x29, 0xed, 0x2b, 0xa1, 0xf9, 0x41, 0x83,
+	0x47, 0x39, 0x1c, 0x28, 0xde, 0xbe, 0x81, 0x66, 0x3a, 0x07, 0xa2, 0xfa, 0xc5, 0x90, 0xf8, 0x64,
+	0xc1, 0x90, 0xb0, 0xb2, 0x68, 0xb4, 0x05, 0x0f, 0x7c, 0xfc, 0x3b, 0x1f, 0xcd, 0x71, 0xd5, 0x14,
+	0xe2, 0xd7, 0x31, 0x5f, 0xe6, 0xb7, 0xb0, 0x76, 0x88, 0x99, 0x13, 0x92, 0xf3, 0x7b, 0x15, 0x88,
+	0xf9, 0x0b, 0xac, 0xe7, 0xeb, 0x51, 0xe1, 0x3c, 0x87, 0x46, 0xfa, 0x85, 0xd4, 0xb2, 0x24, 0x9a,
+	0x0c, 0xd8, 0x3c, 0x84, 0x47, 0x87, 0xd8, 0xc5, 0xfc, 0x7e, 0x2e, 0xae, 0x83, 0x91, 0xa7, 0x25,
+	0x72, 0xd0, 0xfc, 0x1e, 0x8c, 0x01, 0xb6, 0x43, 0xe7, 0x32, 0xb7, 0x24, 0x3b, 0x50, 0x7e, 0x3b,
+	0xc5, 0x61, 0x52, 0xc5, 0xf2, 0x20, 0xa4, 0x2e, 0xf1, 0x08, 0x97, 0xd4, 0x96, 0xad, 0xe8, 0x60,
+	0xfe, 0xa3, 0xc1, 0x5a, 0xae, 0x2a, 0x45, 0xc5, 0x77, 0xb0, 0x12, 0x62, 0x36, 0x75, 0x79, 0x9c,
+	0xd3, 0x2f, 0x52, 0x2c, 0x2c, 0x79, 0xd8, 0xb3, 0xe4, 0x2b, 0x2b, 0x7e, 0x6d, 0x7c, 0xd0, 0xa0,
+	0x12, 0xc9, 0xee, 0x45, 0x2f, 0xda, 0x80, 0xba, 0xea, 0xe6, 0x11, 0x19, 0x8b, 0xe9, 0x59, 0x14,
+	0xab, 0x44, 0x89, 0x8e, 0xc6, 0x0c, 0x19, 0x50, 0x65, 0x3e, 0x09, 0x02, 0x2c, 0x97, 0xad, 0xb8,
+	0x4d, 0xce, 0xe6, 0x15, 0x3c, 0x14, 0x45, 0xac, 0xd6, 0x2d, 0xbb, 0xf3, 0x64, 0xc9, 0x34, 0x7b,
+	0x61, 0x69, 0xb3, 0x17, 0xaf, 0x35, 0xbb, 0xf9, 0x07, 0x74, 0xb2, 0xb6, 0x93, 0x62, 0x9b, 0xfd,
+	0xcc, 0x68, 0x77, 0xfd, 0x99, 0xb9, 0x6d, 0xe7, 0x30, 0x58, 0x7d, 0x4d, 0xfc, 0xf8, 0xfd, 0x5d,
+	0xc3, 0x7e, 0x0c, 0x30, 0xe3, 0x3c, 0x1e, 0x63, 0x09, 0xe5, 0xa9, 0xfd, 0x5c, 0x4c, 0xef, 0x67,
+	0xb3, 0x03, 0x28, 0x6d, 0x34, 0x8a, 0x77, 0xef, 0xaf, 0x0a, 0xd4, 0xfb, 0x97, 0x36, 0x1f, 0xe0,
+	0xf0, 0x1d, 0x71, 0x30, 0x7a, 0x03, 0xab, 0x73, 0x7b, 0x06, 0x3d, 0x49, 0x57, 0xd9, 0x82, 0xdd,
+	0x65, 0x3c, 0x5d, 0x0e, 0x52, 0xfc, 0x4e, 0xa0, 0x93, 0x37, 0xf3, 0xd1, 0x56, 0x96, 0xe5, 0x45,
+	0x6b, 0xc7, 0xd8, 0xbe, 0x11, 0xa7, 0x0c, 0xbd, 0x81, 0xd5, 0xb9, 0x09, 0x99, 0x09, 0x64, 0xd1,
+	0x0e, 0x31, 0x9e, 0x2e, 0x07, 0xcd, 0x02, 0xc9, 0x9b, 0x5a, 0x99, 0x40, 0x96, 0x8c, 0x47, 0x63,
+	0xfb, 0x46, 0x9c, 0x32, 0x64, 0x03, 0x9a, 0x9f, 0x3d, 0xe8, 0x69, 0xe6, 0xf9, 0x82, 0x01, 0x67,
+	0x6c, 0xde, 0x80, 0x52, 0x26, 0xc6, 0xf0, 0x30, 0x67, 0x78, 0xa0, 0xcd, 0x9b, 0x86, 0x4b, 0x64,
+	0x64, 0xeb, 0x76, 0x33, 0x08, 0x9d, 0x42, 0x23, 0xdd, 0x72, 0xe8, 0xd3, 0x6b, 0x3c, 0x5f, 0x9b,
+	0x03, 0xc6, 0xc6, 0xc2, 0x7b, 0xa5, 0xf0, 0x08, 0x60, 0x56, 0xd1, 0x28, 0xfd, 0x3f, 0x3e, 0xd7,
+	0x5d, 0xc6, 0xe3, 0x05, 0xb7, 0x91, 0xaa, 0x83, 0xe6, 0xcf, 0x75, 0xe2, 0x73, 0x1c, 0xfa, 0xb6,
+	0xbb, 0x1b, 0x9c, 0x9f, 0x57, 0xe4, 0xa6, 0xfe, 0xea, 0xff, 0x01, 0x00, 0x0e, 0x1d, 0x1b, 0xb2,
+	0x88, 0x0d, 0x00, 0x00,
 }
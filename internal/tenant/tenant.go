@@ -0,0 +1,194 @@
+// Package tenant resolves the brand a request is served for, and holds the assistant configuration of each brand.
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+
+	"github.com/acai-travel/tech-challenge/internal/httpx"
+	"github.com/twitchtv/twirp"
+)
+
+const (
+	defaultHeader = "X-Tenant-ID"
+	defaultClaim  = "tenant"
+)
+
+// Tenant is a brand served by the deployment. Empty settings fall back to the defaults of the server.
+type Tenant struct {
+	ID   string `json:"-"`
+	Name string `json:"name"`
+
+	// SystemPrompt replaces the default system prompt of the assistant.
+	SystemPrompt string `json:"system_prompt"`
+
+	// Tools lists the names of the tools the assistant may call. All the tools are enabled when it is nil, and none
+	// when it is empty.
+	Tools []string `json:"tools"`
+
+	// HolidayCalendarLink is the ICS calendar of the holidays tool, see HOLIDAY_CALENDAR_LINK.
+	HolidayCalendarLink string `json:"holiday_calendar_link"`
+
+	// Model is the LLM model generating the replies.
+	Model string `json:"model"`
+
+	// DailyTokenQuota replaces the daily token quota of each user, see DAILY_TOKEN_QUOTA. 0 lifts the quota.
+	DailyTokenQuota *int64 `json:"daily_token_quota"`
+}
+
+// ToolEnabled reports whether the assistant may call the tool for the tenant.
+func (t *Tenant) ToolEnabled(name string) bool {
+	return t.Tools == nil || slices.Contains(t.Tools, name)
+}
+
+// Config is the tenant configuration file.
+type Config struct {
+	// Default is the tenant of requests that do not name one. Such requests are rejected when it is empty.
+	Default string `json:"default"`
+
+	// Header and Claim name the request header and the JWT claim holding the tenant ID, X-Tenant-ID and tenant by
+	// default. The claim takes precedence, a header naming another tenant is rejected, as it is for callers
+	// authenticated without the claim, which are bound to the default tenant.
+	Header string `json:"header"`
+	Claim  string `json:"claim"`
+
+	Tenants map[string]*Tenant `json:"tenants"`
+}
+
+// Load reads the tenant configuration file at path.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid tenant configuration %s: %w", path, err)
+	}
+
+	if len(cfg.Tenants) == 0 {
+		return nil, fmt.Errorf("invalid tenant configuration %s: no tenants", path)
+	}
+
+	for id, t := range cfg.Tenants {
+		if t == nil {
+			return nil, fmt.Errorf("invalid tenant configuration %s: tenant %q is empty", path, id)
+		}
+		t.ID = id
+	}
+
+	if _, ok := cfg.Tenants[cfg.Default]; cfg.Default != "" && !ok {
+		return nil, fmt.Errorf("invalid tenant configuration %s: unknown default tenant %q", path, cfg.Default)
+	}
+
+	if cfg.Header == "" {
+		cfg.Header = defaultHeader
+	}
+
+	if cfg.Claim == "" {
+		cfg.Claim = defaultClaim
+	}
+
+	return &cfg, nil
+}
+
+// LoadFromEnv reads the tenant configuration file at TENANTS_FILE. It returns nil when the variable is not set, and
+// the deployment serves a single brand.
+func LoadFromEnv() (*Config, error) {
+	path := os.Getenv("TENANTS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+
+	return Load(path)
+}
+
+// Resolve returns the tenant of the request, from the claims of its principal, see httpx.Auth, or its header. The
+// header only chooses the tenant when authentication is disabled: authenticated callers without a tenant claim, such
+// as API keys, are bound to the default tenant, so that they cannot act for the others.
+func (c *Config) Resolve(r *http.Request) (*Tenant, error) {
+	id := r.Header.Get(c.Header)
+
+	if p, ok := httpx.PrincipalFrom(r.Context()); ok {
+		claim, _ := p.Claims[c.Claim].(string)
+
+		switch {
+		case claim != "":
+			if id != "" && id != claim {
+				return nil, twirp.NewError(twirp.PermissionDenied, "the token does not grant access to tenant "+id)
+			}
+			id = claim
+		case p.Subject != "" || p.Claims != nil:
+			if id != "" && id != c.Default {
+				return nil, twirp.NewError(twirp.PermissionDenied, "the credentials do not grant access to tenant "+id)
+			}
+			id = c.Default
+		}
+	}
+
+	if id == "" {
+		id = c.Default
+	}
+
+	if id == "" {
+		return nil, twirp.RequiredArgumentError(c.Header)
+	}
+
+	t, ok := c.Tenants[id]
+	if !ok {
+		return nil, twirp.InvalidArgumentError(c.Header, "unknown tenant "+id)
+	}
+
+	return t, nil
+}
+
+// Middleware stores the tenant of each request in its context, see FromContext. Requests for no or an unknown tenant
+// are rejected with a Twirp error. It must run after httpx.Auth for claims to be taken into account.
+func (c *Config) Middleware() func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t, err := c.Resolve(r)
+			if err != nil {
+				slog.InfoContext(r.Context(), "HTTP request rejected", "http_method", r.Method, "http_path", r.URL.Path, "error", err)
+				_ = twirp.WriteError(w, err)
+				return
+			}
+
+			handler.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), t)))
+		})
+	}
+}
+
+// Validate checks that the tenants only enable known tools.
+func (c *Config) Validate(tools []string) error {
+	var errs []error
+	for _, t := range c.Tenants {
+		for _, name := range t.Tools {
+			if !slices.Contains(tools, name) {
+				errs = append(errs, fmt.Errorf("tenant %q enables unknown tool %q", t.ID, name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant.
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// FromContext returns the tenant of the request, or nil when the deployment has no tenants.
+func FromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return t
+}
@@ -0,0 +1,100 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/acai-travel/tech-challenge/internal/httpx"
+	"github.com/twitchtv/twirp"
+)
+
+func TestConfig_Resolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	if err := os.WriteFile(path, []byte(`{
+		"default": "acai",
+		"tenants": {
+			"acai": {"name": "Acai Travel"},
+			"sunny": {"name": "Sunny Trips", "model": "gpt-4o-mini", "tools": ["get_weather"]}
+		}
+	}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sunny := cfg.Tenants["sunny"]; sunny.ID != "sunny" || !sunny.ToolEnabled("get_weather") || sunny.ToolEnabled("get_holidays") {
+		t.Errorf("unexpected tenant: %+v", sunny)
+	}
+
+	if !cfg.Tenants["acai"].ToolEnabled("get_holidays") {
+		t.Error("expected all tools to be enabled without a tool list")
+	}
+
+	sunnyToken := &httpx.Principal{Subject: "alice", Claims: map[string]any{"tenant": "sunny"}}
+	apiKey := &httpx.Principal{Subject: "alice"}
+
+	tests := []struct {
+		name      string
+		header    string
+		principal *httpx.Principal
+		want      string
+		code      twirp.ErrorCode
+	}{
+		{name: "default tenant", want: "acai"},
+		{name: "header", header: "sunny", want: "sunny"},
+		{name: "header without authentication", header: "sunny", principal: &httpx.Principal{}, want: "sunny"},
+		{name: "claim", principal: sunnyToken, want: "sunny"},
+		{name: "claim matching header", header: "sunny", principal: sunnyToken, want: "sunny"},
+		{name: "header conflicting with claim", header: "acai", principal: sunnyToken, code: twirp.PermissionDenied},
+		{name: "API key bound to the default tenant", principal: apiKey, want: "acai"},
+		{name: "API key with the default tenant header", header: "acai", principal: apiKey, want: "acai"},
+		{name: "API key with a foreign tenant header", header: "sunny", principal: apiKey, code: twirp.PermissionDenied},
+		{name: "token without tenant claim with a foreign tenant header", header: "sunny", principal: &httpx.Principal{Subject: "alice", Claims: map[string]any{"sub": "alice"}}, code: twirp.PermissionDenied},
+		{name: "unknown tenant", header: "rainy", code: twirp.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/twirp/", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Tenant-ID", tt.header)
+			}
+
+			if tt.principal != nil {
+				r = r.WithContext(httpx.WithPrincipal(r.Context(), tt.principal))
+			}
+
+			got, err := cfg.Resolve(r)
+			if tt.code != "" {
+				if te, ok := err.(twirp.Error); !ok || te.Code() != tt.code {
+					t.Fatalf("expected twirp.%s error, got %v", tt.code, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.ID != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got.ID, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown tools are reported", func(t *testing.T) {
+		if err := cfg.Validate([]string{"get_weather"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := cfg.Validate([]string{"get_holidays"}); err == nil {
+			t.Error("expected error for unknown tool, got nil")
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package weather
+
+import (
+	"container/list"
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultCacheSize is the number of responses kept by the cache when no size is configured.
+	DefaultCacheSize = 1000
+
+	// DefaultForecastTTL is how long forecasts are cached when no TTL is configured.
+	DefaultForecastTTL = time.Hour
+
+	// currentRefresh is how often WeatherAPI refreshes the current conditions, which are cached until the next
+	// refresh after their last_updated_epoch.
+	currentRefresh = 15 * time.Minute
+
+	// minCurrentTTL caches current conditions past their expected refresh, as WeatherAPI may publish it late.
+	minCurrentTTL = time.Minute
+)
+
+// Cache stores WeatherAPI responses, as raw JSON, so that the same location is not requested repeatedly. Backends
+// shared between the instances of a deployment, e.g. on Redis, let them reuse each other's responses.
+type Cache interface {
+	// Get returns the response stored at key, if any and not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores the response at key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// LRUCache is a Cache local to the process, which evicts the least recently used responses once full.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{size: size, entries: map[string]*list.Element{}, order: list.New(), now: time.Now}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := e.Value.(*lruEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(e)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(e)
+	return entry.value, true, nil
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry{key: key, value: value, expires: c.now().Add(ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+
+	return nil
+}
+
+// cacheKey identifies the response of the endpoint to the params, which must not include the API key. Locations are
+// normalized so that "Barcelona" and " barcelona " share a response.
+func cacheKey(endpoint string, params url.Values) string {
+	normalized := url.Values{}
+	for k, v := range params {
+		normalized[k] = v
+	}
+
+	if q := params.Get("q"); q != "" {
+		normalized.Set("q", strings.Join(strings.Fields(strings.ToLower(q)), " "))
+	}
+
+	return endpoint + "?" + normalized.Encode()
+}
+
+// currentTTL caches the current conditions until WeatherAPI is expected to refresh them.
+func currentTTL(current CurrentWeather, now time.Time) time.Duration {
+	if current.LastUpdatedEpoch == 0 {
+		return minCurrentTTL
+	}
+
+	next := time.Unix(int64(current.LastUpdatedEpoch), 0).Add(currentRefresh)
+	return min(max(next.Sub(now), minCurrentTTL), currentRefresh)
+}
+
+var cacheRequests, _ = otel.Meter("weather").Int64Counter("weather.cache.requests",
+	metric.WithDescription("WeatherAPI responses looked up in the cache, by endpoint and result (hit or miss)."))
+
+func recordCacheLookup(ctx context.Context, endpoint string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	cacheRequests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("endpoint", endpoint),
+		attribute.String("result", result),
+	))
+}
@@ -0,0 +1,190 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	cache := NewLRUCache(2)
+	cache.now = func() time.Time { return now }
+
+	_ = cache.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = cache.Set(ctx, "b", []byte("2"), time.Minute)
+
+	// Reading a makes b the least recently used entry, evicted by c.
+	if v, ok, _ := cache.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v, want %q", v, ok, "1")
+	}
+
+	_ = cache.Set(ctx, "c", []byte("3"), time.Hour)
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, ok, _ := cache.Get(ctx, "a"); ok {
+		t.Errorf("expected a to be expired")
+	}
+
+	if v, ok, _ := cache.Get(ctx, "c"); !ok || string(v) != "3" {
+		t.Errorf("Get(c) = %q, %v, want %q", v, ok, "3")
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+	lastUpdated := time.Now().Add(-5 * time.Minute).Unix()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = fmt.Fprintf(w, `{"location":{"name":"Barcelona"},"current":{"last_updated_epoch":%d,"temp_c":21.5}}`, lastUpdated)
+	}))
+	defer srv.Close()
+
+	cache := NewLRUCache(10)
+	cli := NewClient(Config{BaseURL: srv.URL, APIKey: "secret", HTTPClient: srv.Client(), Cache: cache})
+
+	for _, location := range []string{"Barcelona", " barcelona", "BARCELONA "} {
+		resp, err := cli.GetCurrentWeather(ctx, location, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if resp.Current.TempC != 21.5 {
+			t.Errorf("unexpected current weather: %+v", resp)
+		}
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected normalized locations to share a cached response, got %d requests", n)
+	}
+
+	if _, err := cli.GetWeatherForecast(ctx, "Barcelona", 3, false, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cli.GetCurrentWeather(ctx, "Barcelona", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := requests.Load(); n != 3 {
+		t.Errorf("expected endpoints and parameters to be cached apart, got %d requests", n)
+	}
+
+	// The conditions were updated 5 minutes ago, WeatherAPI refreshes them 10 minutes later.
+	cache.now = func() time.Time { return time.Now().Add(11 * time.Minute) }
+
+	if _, err := cli.GetCurrentWeather(ctx, "Barcelona", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := requests.Load(); n != 4 {
+		t.Errorf("expected current conditions to expire at the next refresh, got %d requests", n)
+	}
+}
+
+func TestCurrentTTL(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		lastUpdated time.Time
+		want        time.Duration
+	}{
+		{name: "until the next refresh", lastUpdated: now.Add(-5 * time.Minute), want: 10 * time.Minute},
+		{name: "late refresh", lastUpdated: now.Add(-time.Hour), want: minCurrentTTL},
+		{name: "clock skew", lastUpdated: now.Add(time.Hour), want: currentRefresh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := currentTTL(CurrentWeather{LastUpdatedEpoch: int(tt.lastUpdated.Unix())}, now)
+			if got != tt.want {
+				t.Errorf("currentTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// metricReader collects the metrics of the package. The global provider is set once, as the instruments are bound to
+// the first one.
+var metricReader = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
+
+// cacheLookups returns the cache lookups recorded so far, by endpoint and result.
+func cacheLookups(t *testing.T) map[string]int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := metricReader().Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "weather.cache.requests" {
+				continue
+			}
+
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				endpoint, _ := dp.Attributes.Value("endpoint")
+				result, _ := dp.Attributes.Value("result")
+				counts[endpoint.AsString()+" "+result.AsString()] = dp.Value
+			}
+		}
+	}
+
+	return counts
+}
+
+func TestClient_CacheMetrics(t *testing.T) {
+	ctx := context.Background()
+	before := cacheLookups(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"location":{"name":"Barcelona"},"current":{"last_updated_epoch":%d,"temp_c":21.5}}`, time.Now().Unix())
+	}))
+	defer srv.Close()
+
+	cli := NewClient(Config{BaseURL: srv.URL, HTTPClient: srv.Client(), Cache: NewLRUCache(10)})
+
+	for range 3 {
+		if _, err := cli.GetCurrentWeather(ctx, "Barcelona", false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := cli.GetWeatherForecast(ctx, "Barcelona", 3, false, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	after := cacheLookups(t)
+	want := map[string]int64{"current.json miss": 1, "current.json hit": 2, "forecast.json miss": 1, "forecast.json hit": 0}
+	for key, n := range want {
+		if got := after[key] - before[key]; got != n {
+			t.Errorf("%s lookups = %d, want %d", key, got, n)
+		}
+	}
+}
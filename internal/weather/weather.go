@@ -7,11 +7,22 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const API_URL = "http://api.weatherapi.com/v1"
+const (
+	// DefaultBaseURL is the WeatherAPI endpoint used when no base URL is configured.
+	DefaultBaseURL = "https://api.weatherapi.com/v1"
+
+	// DefaultTimeout bounds each WeatherAPI request when no timeout is configured.
+	DefaultTimeout = 10 * time.Second
+)
 
 type Condition struct {
 	Text string `json:"text"`
@@ -50,10 +61,47 @@ type CurrentWeather struct {
 	Uv               float64   `json:"uv"`
 	GustMph          float64   `json:"gust_mph"`
 	GustKph          float64   `json:"gust_kph"`
-	ShortRad         int       `json:"short_rad"`
-	DiffRad          int       `json:"diff_rad"`
-	DNI              int       `json:"dni"`
-	GTI              int       `json:"gti"`
+	ShortRad         float64   `json:"short_rad"`
+	DiffRad          float64   `json:"diff_rad"`
+	DNI              float64   `json:"dni"`
+	GTI              float64   `json:"gti"`
+
+	// AirQuality is only set when requested.
+	AirQuality *AirQuality `json:"air_quality,omitempty"`
+}
+
+// Struct for the "air_quality" field, pollutant concentrations are in μg/m3
+type AirQuality struct {
+	CO           float64 `json:"co"`
+	NO2          float64 `json:"no2"`
+	O3           float64 `json:"o3"`
+	SO2          float64 `json:"so2"`
+	PM2_5        float64 `json:"pm2_5"`
+	PM10         float64 `json:"pm10"`
+	USEPAIndex   int     `json:"us-epa-index"`
+	GBDefraIndex int     `json:"gb-defra-index"`
+}
+
+// Struct for each element of the "alert" field inside "alerts"
+type Alert struct {
+	Headline    string `json:"headline"`
+	MsgType     string `json:"msgtype"`
+	Severity    string `json:"severity"`
+	Urgency     string `json:"urgency"`
+	Areas       string `json:"areas"`
+	Category    string `json:"category"`
+	Certainty   string `json:"certainty"`
+	Event       string `json:"event"`
+	Note        string `json:"note"`
+	Effective   string `json:"effective"`
+	Expires     string `json:"expires"`
+	Desc        string `json:"desc"`
+	Instruction string `json:"instruction"`
+}
+
+// Struct for the "alerts" field
+type Alerts struct {
+	Alert []Alert `json:"alert"`
 }
 
 // Struct for the "location" field
@@ -76,15 +124,88 @@ type WeatherResponse struct {
 
 // Struct for the "day" field inside "forecastday"
 type Day struct {
-	Condition Condition `json:"condition"`
-	// Ommitted fields
+	MaxtempC          float64   `json:"maxtemp_c"`
+	MaxtempF          float64   `json:"maxtemp_f"`
+	MintempC          float64   `json:"mintemp_c"`
+	MintempF          float64   `json:"mintemp_f"`
+	AvgtempC          float64   `json:"avgtemp_c"`
+	AvgtempF          float64   `json:"avgtemp_f"`
+	MaxwindMph        float64   `json:"maxwind_mph"`
+	MaxwindKph        float64   `json:"maxwind_kph"`
+	TotalprecipMm     float64   `json:"totalprecip_mm"`
+	TotalprecipIn     float64   `json:"totalprecip_in"`
+	TotalsnowCm       float64   `json:"totalsnow_cm"`
+	AvgvisKm          float64   `json:"avgvis_km"`
+	AvgvisMiles       float64   `json:"avgvis_miles"`
+	Avghumidity       float64   `json:"avghumidity"`
+	DailyWillItRain   int       `json:"daily_will_it_rain"`
+	DailyChanceOfRain int       `json:"daily_chance_of_rain"`
+	DailyWillItSnow   int       `json:"daily_will_it_snow"`
+	DailyChanceOfSnow int       `json:"daily_chance_of_snow"`
+	Condition         Condition `json:"condition"`
+	Uv                float64   `json:"uv"`
+
+	// AirQuality is only set when requested.
+	AirQuality *AirQuality `json:"air_quality,omitempty"`
+}
+
+// Struct for the "astro" field inside "forecastday"
+type Astro struct {
+	Sunrise          string  `json:"sunrise"`
+	Sunset           string  `json:"sunset"`
+	Moonrise         string  `json:"moonrise"`
+	Moonset          string  `json:"moonset"`
+	MoonPhase        string  `json:"moon_phase"`
+	MoonIllumination float64 `json:"moon_illumination"`
+	IsMoonUp         int     `json:"is_moon_up"`
+	IsSunUp          int     `json:"is_sun_up"`
+}
+
+// Struct for each element of the "hour" field inside "forecastday"
+type Hour struct {
+	TimeEpoch    int       `json:"time_epoch"`
+	Time         string    `json:"time"`
+	TempC        float64   `json:"temp_c"`
+	TempF        float64   `json:"temp_f"`
+	IsDay        int       `json:"is_day"`
+	Condition    Condition `json:"condition"`
+	WindMph      float64   `json:"wind_mph"`
+	WindKph      float64   `json:"wind_kph"`
+	WindDegree   int       `json:"wind_degree"`
+	WindDir      string    `json:"wind_dir"`
+	PressureMb   float64   `json:"pressure_mb"`
+	PressureIn   float64   `json:"pressure_in"`
+	PrecipMm     float64   `json:"precip_mm"`
+	PrecipIn     float64   `json:"precip_in"`
+	SnowCm       float64   `json:"snow_cm"`
+	Humidity     int       `json:"humidity"`
+	Cloud        int       `json:"cloud"`
+	FeelslikeC   float64   `json:"feelslike_c"`
+	FeelslikeF   float64   `json:"feelslike_f"`
+	WindchillC   float64   `json:"windchill_c"`
+	WindchillF   float64   `json:"windchill_f"`
+	HeatindexC   float64   `json:"heatindex_c"`
+	HeatindexF   float64   `json:"heatindex_f"`
+	DewpointC    float64   `json:"dewpoint_c"`
+	DewpointF    float64   `json:"dewpoint_f"`
+	WillItRain   int       `json:"will_it_rain"`
+	ChanceOfRain int       `json:"chance_of_rain"`
+	WillItSnow   int       `json:"will_it_snow"`
+	ChanceOfSnow int       `json:"chance_of_snow"`
+	VisKm        float64   `json:"vis_km"`
+	VisMiles     float64   `json:"vis_miles"`
+	GustMph      float64   `json:"gust_mph"`
+	GustKph      float64   `json:"gust_kph"`
+	Uv           float64   `json:"uv"`
 }
 
 // Struct for each "forecastday"
 type ForecastDay struct {
-	Date string `json:"date"`
-	Day  Day    `json:"day"`
-	// ommitted fields
+	Date      string `json:"date"`
+	DateEpoch int    `json:"date_epoch"`
+	Day       Day    `json:"day"`
+	Astro     Astro  `json:"astro"`
+	Hour      []Hour `json:"hour"`
 }
 
 // Struct for the "forecast" field
@@ -94,66 +215,216 @@ type Forecast struct {
 
 // Struct for the full forecast response
 type WeatherForecastResponse struct {
-	Forecast Forecast `json:"forecast"`
-	// ...other fields omitted...
+	Location Location       `json:"location"`
+	Current  CurrentWeather `json:"current"`
+	Forecast Forecast       `json:"forecast"`
+
+	// Alerts are only set when requested.
+	Alerts Alerts `json:"alerts"`
 }
 
-func GetCurrentWeather(ctx context.Context, location string) (CurrentWeather, error) {
-	weatherKey := os.Getenv("WEATHER_API_KEY")
+// TimeLayout is the layout of the local date times in WeatherAPI responses, like Location.Localtime and Hour.Time.
+const TimeLayout = "2006-01-02 15:04"
 
-	slog.InfoContext(ctx, "Fetching weather forecast", "location", location)
-	url := fmt.Sprintf("%s/v1/current.json?key=%s&q=%s&aqi=no", API_URL, weatherKey, location)
-	resp, err := http.Get(url)
-	if err != nil {
-		return CurrentWeather{}, err
+// Config holds the settings of a Client.
+type Config struct {
+	// BaseURL of the WeatherAPI, including the version path. Defaults to DefaultBaseURL.
+	BaseURL string
+	APIKey  string
+
+	// HTTPClient used to send requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Timeout applied to each request on top of the caller's context. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// Cache stores the responses, nil disables caching. Current conditions are cached until WeatherAPI refreshes
+	// them, forecasts for ForecastTTL, which defaults to DefaultForecastTTL.
+	Cache       Cache
+	ForecastTTL time.Duration
+}
+
+// ConfigFromEnv reads the client configuration from WEATHER_API_KEY, WEATHER_API_URL and WEATHER_API_TIMEOUT.
+// Responses are cached in memory, WEATHER_CACHE_SIZE sets the number of responses kept (0 disables the cache) and
+// WEATHER_FORECAST_TTL how long forecasts are kept.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		BaseURL: os.Getenv("WEATHER_API_URL"),
+		APIKey:  os.Getenv("WEATHER_API_KEY"),
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		// Returns error if status is not 200
-		body, _ := io.ReadAll(resp.Body)
-		return CurrentWeather{}, errors.New(string(body))
+	if v, err := time.ParseDuration(os.Getenv("WEATHER_API_TIMEOUT")); err == nil {
+		cfg.Timeout = v
 	}
 
-	var weather WeatherResponse
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&weather); err != nil {
-		return CurrentWeather{}, err
+	if v, err := time.ParseDuration(os.Getenv("WEATHER_FORECAST_TTL")); err == nil {
+		cfg.ForecastTTL = v
+	}
+
+	size := DefaultCacheSize
+	if v := os.Getenv("WEATHER_CACHE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			size = n
+		} else {
+			slog.Warn("Ignoring invalid WEATHER_CACHE_SIZE", "value", v)
+		}
+	}
+
+	if size > 0 {
+		cfg.Cache = NewLRUCache(size)
 	}
-	return weather.Current, nil
 
+	return cfg
 }
 
-func GetWeatherForecast(ctx context.Context, location string, days int, alerts bool, air_quality bool) (Forecast, error) {
-	weatherKey := os.Getenv("WEATHER_API_KEY")
+// Client is a WeatherAPI client. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+	timeout time.Duration
 
+	cache       Cache
+	forecastTTL time.Duration
+}
+
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+		http:    cfg.HTTPClient,
+		timeout: cfg.Timeout,
+
+		cache:       cfg.Cache,
+		forecastTTL: cfg.ForecastTTL,
+	}
+
+	if c.baseURL == "" {
+		c.baseURL = DefaultBaseURL
+	}
+
+	if c.http == nil {
+		c.http = http.DefaultClient
+	}
+
+	if c.timeout <= 0 {
+		c.timeout = DefaultTimeout
+	}
+
+	if c.forecastTTL <= 0 {
+		c.forecastTTL = DefaultForecastTTL
+	}
+
+	return c
+}
+
+// GetCurrentWeather returns the current conditions at the given location, along with the location it resolved to.
+func (c *Client) GetCurrentWeather(ctx context.Context, location string, airQuality bool) (WeatherResponse, error) {
+	slog.InfoContext(ctx, "Fetching current weather", "location", location)
+
+	var weather WeatherResponse
+	if err := c.get(ctx, "current.json", url.Values{
+		"q":   {location},
+		"aqi": {boolToYesNo(airQuality)},
+	}, &weather, func() time.Duration { return currentTTL(weather.Current, time.Now()) }); err != nil {
+		return WeatherResponse{}, err
+	}
+
+	return weather, nil
+}
+
+// GetWeatherForecast returns the daily and hourly forecast for the given number of days at the given location,
+// along with the location it resolved to.
+func (c *Client) GetWeatherForecast(ctx context.Context, location string, days int, alerts bool, airQuality bool) (WeatherForecastResponse, error) {
 	slog.InfoContext(ctx, "Fetching weather forecast", "location", location, "days", days)
-	url := fmt.Sprintf("%s/v1/forecast.json?key=%s&q=%s&days=%d&aqi=%s&alerts=%s",
-		API_URL,
-		weatherKey, location, days,
-		boolToYesNo(air_quality), boolToYesNo(alerts),
-	)
-	resp, err := http.Get(url)
+
+	var forecastResp WeatherForecastResponse
+	if err := c.get(ctx, "forecast.json", url.Values{
+		"q":      {location},
+		"days":   {fmt.Sprint(days)},
+		"aqi":    {boolToYesNo(airQuality)},
+		"alerts": {boolToYesNo(alerts)},
+	}, &forecastResp, func() time.Duration { return c.forecastTTL }); err != nil {
+		return WeatherForecastResponse{}, err
+	}
+
+	return forecastResp, nil
+}
+
+// get calls the given API endpoint and decodes the JSON response into out. Responses are served from the cache when
+// possible, and otherwise cached for the duration ttl returns once out is decoded.
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values, out any, ttl func() time.Duration) error {
+	if c.cache == nil {
+		_, err := c.fetch(ctx, endpoint, params, out)
+		return err
+	}
+
+	key := cacheKey(endpoint, params)
+
+	b, ok, err := c.cache.Get(ctx, key)
 	if err != nil {
-		// Error can occur here if the HTTP request fails (network, DNS, etc.)
-		return Forecast{}, err
+		slog.WarnContext(ctx, "Failed to read weather cache", "endpoint", endpoint, "error", err)
+	}
+
+	if ok && json.Unmarshal(b, out) == nil {
+		recordCacheLookup(ctx, endpoint, true)
+		return nil
+	}
+
+	recordCacheLookup(ctx, endpoint, false)
+
+	b, err = c.fetch(ctx, endpoint, params, out)
+	if err != nil {
+		return err
+	}
+
+	if d := ttl(); d > 0 {
+		if err := c.cache.Set(ctx, key, b, d); err != nil {
+			slog.WarnContext(ctx, "Failed to write weather cache", "endpoint", endpoint, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// fetch requests the given API endpoint, decodes the JSON response into out and returns it.
+func (c *Client) fetch(ctx context.Context, endpoint string, params url.Values, out any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	params = maps.Clone(params)
+	params.Set("key", c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// Error can occur here if the HTTP request fails (network, DNS, timeout, etc.). The URL is dropped from the
+		// error as it contains the API key.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("weather API request failed: %w", urlErr.Err)
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		// Error can occur here if the API returns a non-200 status (bad request, unauthorized, etc.)
 		body, _ := io.ReadAll(resp.Body)
-		return Forecast{}, errors.New(string(body))
+		return nil, fmt.Errorf("weather API responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
 	}
 
-	var forecastResp WeatherForecastResponse
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&forecastResp); err != nil {
-		// Error can occur here if the response body is not valid JSON or doesn't match the struct
-		return Forecast{}, err
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	return forecastResp.Forecast, nil
+	// Error can occur here if the response body is not valid JSON or doesn't match the struct
+	return b, json.Unmarshal(b, out)
 }
 
 // Helper function to convert bool to "yes"/"no" string for API params
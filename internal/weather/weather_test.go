@@ -0,0 +1,156 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetCurrentWeather(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends key and location to current.json", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/current.json" {
+				t.Errorf("unexpected path %q", r.URL.Path)
+			}
+
+			if got := r.URL.Query().Get("key"); got != "secret" {
+				t.Errorf("key = %q, want %q", got, "secret")
+			}
+
+			if got := r.URL.Query().Get("q"); got != "Sant Cugat del Vallès" {
+				t.Errorf("q = %q, want %q", got, "Sant Cugat del Vallès")
+			}
+
+			_, _ = w.Write([]byte(`{"location":{"name":"Sant Cugat del Valles"},"current":{"temp_c":21.5,"condition":{"text":"Sunny"}}}`))
+		}))
+		defer srv.Close()
+
+		cli := NewClient(Config{BaseURL: srv.URL + "/v1/", APIKey: "secret", HTTPClient: srv.Client()})
+
+		resp, err := cli.GetCurrentWeather(ctx, "Sant Cugat del Vallès", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if resp.Location.Name != "Sant Cugat del Valles" || resp.Current.TempC != 21.5 || resp.Current.Condition.Text != "Sunny" {
+			t.Errorf("unexpected current weather: %+v", resp)
+		}
+	})
+
+	t.Run("non 200 responses are errors", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error":{"code":1006,"message":"No matching location found."}}`, http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		_, err := NewClient(Config{BaseURL: srv.URL, HTTPClient: srv.Client()}).GetCurrentWeather(ctx, "Nowhere", false)
+		if err == nil || !strings.Contains(err.Error(), "No matching location found.") {
+			t.Fatalf("expected API error, got %v", err)
+		}
+	})
+
+	t.Run("requests are bounded by the timeout and do not leak the key", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer srv.Close()
+
+		cli := NewClient(Config{BaseURL: srv.URL, APIKey: "secret", HTTPClient: srv.Client(), Timeout: 50 * time.Millisecond})
+
+		_, err := cli.GetCurrentWeather(ctx, "Barcelona", false)
+		if err == nil {
+			t.Fatal("expected timeout error, got nil")
+		}
+
+		if strings.Contains(err.Error(), "secret") {
+			t.Errorf("error leaks the API key: %v", err)
+		}
+	})
+}
+
+// recorded serves the WeatherAPI response recorded in testdata/name, checking the query against want.
+func recorded(t *testing.T, name string, want url.Values) *Client {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read recorded response: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k := range want {
+			if got := r.URL.Query().Get(k); got != want.Get(k) {
+				t.Errorf("%s = %q, want %q", k, got, want.Get(k))
+			}
+		}
+
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewClient(Config{BaseURL: srv.URL, HTTPClient: srv.Client()})
+}
+
+func TestClient_AirQuality(t *testing.T) {
+	cli := recorded(t, "current_air_quality.json", url.Values{"q": {"Madrid"}, "aqi": {"yes"}})
+
+	resp, err := cli.GetCurrentWeather(context.Background(), "Madrid", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &AirQuality{CO: 258.1, NO2: 14.615, O3: 148, SO2: 4.44, PM2_5: 12.395, PM10: 15.725, USEPAIndex: 2, GBDefraIndex: 3}
+	if !cmp.Equal(resp.Current.AirQuality, want) {
+		t.Errorf("air quality mismatch (-got +want):\n%s", cmp.Diff(resp.Current.AirQuality, want))
+	}
+
+	if resp.Location.TzID != "Europe/Madrid" || resp.Current.ShortRad != 818.7 {
+		t.Errorf("unexpected current weather: %+v", resp)
+	}
+}
+
+func TestClient_Alerts(t *testing.T) {
+	cli := recorded(t, "forecast_alerts.json", url.Values{"q": {"Valencia"}, "days": {"1"}, "alerts": {"yes"}})
+
+	resp, err := cli.GetWeatherForecast(context.Background(), "Valencia", 1, true, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Alerts.Alert) != 2 {
+		t.Fatalf("expected 2 alerts, got %+v", resp.Alerts)
+	}
+
+	want := Alert{
+		Headline:    "Aviso rojo. Lluvias en litoral sur de Valencia",
+		MsgType:     "Alert",
+		Severity:    "Extreme",
+		Urgency:     "Immediate",
+		Areas:       "Litoral sur de Valencia",
+		Category:    "Met",
+		Certainty:   "Likely",
+		Event:       "Aviso rojo de lluvias",
+		Effective:   "2025-10-22T09:00:00+02:00",
+		Expires:     "2025-10-22T23:59:59+02:00",
+		Desc:        "Precipitación acumulada en una hora: 90 mm.",
+		Instruction: "Evite desplazamientos.",
+	}
+
+	if got := resp.Alerts.Alert[0]; got != want {
+		t.Errorf("alert mismatch (-got +want):\n%s", cmp.Diff(got, want))
+	}
+
+	if day := resp.Forecast.Forecastday; len(day) != 1 || day[0].Day.TotalprecipMm != 96.4 || day[0].Astro.MoonPhase != "New Moon" {
+		t.Errorf("unexpected forecast: %+v", resp.Forecast)
+	}
+}